@@ -0,0 +1,91 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/kadaan/log4shell-scanner/lib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"os"
+	"time"
+)
+
+var (
+	baselineCmd = &cobra.Command{
+		Use:   "baseline",
+		Short: "Manage suppression baselines.",
+	}
+	baselineCreateCmd = &cobra.Command{
+		Use:   "create [flags]",
+		Short: "Scan and write a suppression file accepting every current match.",
+		Long: `Scan using the same flags as the scanner and write a suppression file containing an entry for every
+match, keyed by its path and hash.  Review the generated justifications before committing the baseline.`,
+		Example:               `  log4shell-scanner baseline create --root=. --owner=team@example.com --justification="Mitigated, see JIRA-123"`,
+		Args:                  cobra.NoArgs,
+		PreRunE:               pre,
+		RunE:                  createBaseline,
+		DisableFlagsInUseLine: true,
+	}
+	baselineOutput        string
+	baselineOwner         string
+	baselineJustification string
+	baselineExpires       string
+)
+
+func newBaselineCmd(scanFlags *pflag.FlagSet) *cobra.Command {
+	defaultExpires := time.Now().AddDate(0, 0, 90).Format("2006-01-02")
+	baselineCreateCmd.Flags().StringVarP(&baselineOutput, "output", "o", "suppressions.yaml", "File to write the suppressions to")
+	_ = baselineCreateCmd.MarkFlagFilename("output", "yaml", "yml")
+	baselineCreateCmd.Flags().StringVar(&baselineOwner, "owner", "", "Owner recorded on every suppression")
+	_ = baselineCreateCmd.MarkFlagRequired("owner")
+	baselineCreateCmd.Flags().StringVar(&baselineJustification, "justification", "", "Justification recorded on every suppression")
+	_ = baselineCreateCmd.MarkFlagRequired("justification")
+	baselineCreateCmd.Flags().StringVar(&baselineExpires, "expires", defaultExpires, "Date, in the form 2006-01-02, on which every suppression expires")
+	baselineCreateCmd.Flags().AddFlagSet(scanFlags)
+	baselineCmd.AddCommand(baselineCreateCmd)
+	return baselineCmd
+}
+
+func createBaseline(_ *cobra.Command, _ []string) error {
+	expires, err := time.ParseInLocation("2006-01-02", baselineExpires, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid expires, expected 2006-01-02: %s", baselineExpires)
+	}
+
+	result, err := scan()
+	if err != nil {
+		return err
+	}
+	baseline := lib.Suppressions{}
+	for _, m := range result.GetMatches() {
+		baseline.Suppressions = append(baseline.Suppressions,
+			lib.NewSuppression(lib.EscapeGlob(lib.JoinPath(m.Path())), m.Hash(), baselineJustification, baselineOwner, expires))
+	}
+
+	f, err := os.Create(baselineOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create baseline: %v", err)
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(f)
+	if err := baseline.Write(f); err != nil {
+		return fmt.Errorf("failed to write baseline: %v", err)
+	}
+	fmt.Printf("%s\nWrote %d suppressions to %s\n", lib.ResetLine, len(baseline.Suppressions), baselineOutput)
+	return nil
+}
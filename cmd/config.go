@@ -0,0 +1,235 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	envPrefix   = "LOG4SHELL_SCANNER_"
+	profilesKey = "profiles"
+)
+
+var (
+	configFile    string
+	configProfile string
+	configCmd     = &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the scanner configuration.",
+	}
+	configPrintCmd = &cobra.Command{
+		Use:                   "print [flags]",
+		Short:                 "Print the effective configuration after merging the config file, profile, environment and flags.",
+		Example:               `  log4shell-scanner config print --config=scanner.yaml --profile=ci`,
+		Args:                  cobra.NoArgs,
+		PreRunE:               loadConfig,
+		RunE:                  printConfig,
+		DisableFlagsInUseLine: true,
+	}
+	configExcludedFlags = map[string]struct{}{
+		"config":  {},
+		"profile": {},
+		"help":    {},
+		"version": {},
+	}
+)
+
+// addConfigFlags adds the config flags to the scan command.  They are shared with the commands which
+// accept the scan flags, each of which must apply loadConfig, as the other commands have flags of
+// their own which the config file and environment variables must not set.
+func addConfigFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&configFile, "config", "", fmt.Sprintf("YAML or TOML file containing flag values (env: %sCONFIG)", envPrefix))
+	_ = cmd.MarkFlagFilename("config", "yaml", "yml", "toml")
+	cmd.Flags().StringVar(&configProfile, "profile", "", fmt.Sprintf("Named profile from the config file to apply (env: %sPROFILE)", envPrefix))
+}
+
+func newConfigCmd(scanFlags *pflag.FlagSet) *cobra.Command {
+	configPrintCmd.Flags().AddFlagSet(scanFlags)
+	configCmd.AddCommand(configPrintCmd)
+	return configCmd
+}
+
+// loadConfig applies configuration to every flag not set on the command line.  Values are taken, in
+// order of precedence, from LOG4SHELL_SCANNER_* environment variables, the selected profile, and
+// the top level of the config file.
+func loadConfig(cmd *cobra.Command, _ []string) error {
+	if !cmd.Flags().Changed("config") {
+		configFile = os.Getenv(envPrefix + "CONFIG")
+	}
+	if !cmd.Flags().Changed("profile") {
+		if profile, ok := os.LookupEnv(envPrefix + "PROFILE"); ok {
+			configProfile = profile
+		}
+	}
+
+	values, err := readConfig(configFile, configProfile)
+	if err != nil {
+		return err
+	}
+
+	var flagErr error
+	known := map[string]struct{}{}
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		known[flag.Name] = struct{}{}
+		if _, excluded := configExcludedFlags[flag.Name]; excluded || flag.Changed || flagErr != nil {
+			return
+		}
+		if value, ok := os.LookupEnv(envName(flag.Name)); ok {
+			if err := flag.Value.Set(value); err != nil {
+				flagErr = fmt.Errorf("invalid value for %s: %v", envName(flag.Name), err)
+			}
+			return
+		}
+		if value, ok := values[flag.Name]; ok {
+			if err := setFlagValue(flag, value); err != nil {
+				flagErr = fmt.Errorf("invalid value for %s in %s: %v", flag.Name, configFile, err)
+			}
+		}
+	})
+	if flagErr != nil {
+		return flagErr
+	}
+	for key := range values {
+		if _, ok := known[key]; !ok {
+			return fmt.Errorf("unknown key in %s: %s", configFile, key)
+		}
+	}
+	return nil
+}
+
+func readConfig(file string, profile string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if len(file) == 0 {
+		if len(profile) > 0 {
+			return nil, fmt.Errorf("profile %s requires a config file", profile)
+		}
+		return values, nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %v", err)
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".toml":
+		err = toml.Unmarshal(content, &values)
+	default:
+		err = yaml.Unmarshal(content, &values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", file, err)
+	}
+
+	profiles := map[string]interface{}{}
+	if p, ok := values[profilesKey]; ok {
+		profiles, ok = p.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s in %s", profilesKey, file)
+		}
+		delete(values, profilesKey)
+	}
+	if len(profile) > 0 {
+		p, ok := profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("profile %s not found in %s", profile, file)
+		}
+		profileValues, ok := p.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid profile %s in %s", profile, file)
+		}
+		for k, v := range profileValues {
+			values[k] = v
+		}
+	}
+	return values, nil
+}
+
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func setFlagValue(flag *pflag.Flag, value interface{}) error {
+	var values []string
+	isList := false
+	switch v := value.(type) {
+	case []interface{}:
+		isList = true
+		for _, i := range v {
+			values = append(values, fmt.Sprint(i))
+		}
+	default:
+		values = []string{fmt.Sprint(v)}
+	}
+	if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+		return sliceValue.Replace(values)
+	}
+	if isList && flag.Value.Type() != "profileMode" {
+		return fmt.Errorf("expected a single value but got a list")
+	}
+	return flag.Value.Set(strings.Join(values, ","))
+}
+
+func printConfig(cmd *cobra.Command, _ []string) error {
+	values := map[string]interface{}{}
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if _, excluded := configExcludedFlags[flag.Name]; excluded {
+			return
+		}
+		values[flag.Name] = flagValue(flag)
+	})
+	if len(configFile) > 0 {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "# config: %s\n", configFile)
+	}
+	if len(configProfile) > 0 {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "# profile: %s\n", configProfile)
+	}
+	content, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(content)
+	return err
+}
+
+func flagValue(flag *pflag.Flag) interface{} {
+	if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+		return sliceValue.GetSlice()
+	}
+	switch flag.Value.Type() {
+	case "bool":
+		if b, err := strconv.ParseBool(flag.Value.String()); err == nil {
+			return b
+		}
+	case "int", "count":
+		if i, err := strconv.Atoi(flag.Value.String()); err == nil {
+			return i
+		}
+	case "profileMode":
+		if len(flag.Value.String()) == 0 {
+			return []string{}
+		}
+		return strings.Split(flag.Value.String(), ",")
+	}
+	return flag.Value.String()
+}
@@ -0,0 +1,188 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/jwalton/gchalk"
+	"github.com/kadaan/log4shell-scanner/lib"
+	"github.com/spf13/cobra"
+	"io"
+	"strings"
+)
+
+var (
+	diffCmd = &cobra.Command{
+		Use:   "diff [flags] BEFORE AFTER",
+		Short: "Compare two JSON scan reports.",
+		Long: `Compare two JSON scan reports written with --report and show new, resolved and changed matches and
+new, resolved and changed failures.  Matches and failures are identified by their path, so a file
+replaced in place is a changed match whose hash differs.
+
+Exit codes:
+  0  no regressions
+  1  the reports could not be compared
+  2  new matches, or matches with additional match types
+  4  new scan failures, or failures with additional messages
+  6  both new matches and new scan failures`,
+		Example:               `  log4shell-scanner diff last-week.json this-week.json --format=markdown`,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  diff,
+		DisableFlagsInUseLine: true,
+	}
+	diffFormat string
+)
+
+func newDiffCmd() *cobra.Command {
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "human", "Output format, one of human,json,markdown")
+	return diffCmd
+}
+
+func diff(cmd *cobra.Command, args []string) error {
+	before, err := lib.LoadReport(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load report %s: %v", args[0], err)
+	}
+	after, err := lib.LoadReport(args[1])
+	if err != nil {
+		return fmt.Errorf("failed to load report %s: %v", args[1], err)
+	}
+	d := lib.DiffReports(before, after)
+
+	w := cmd.OutOrStdout()
+	switch diffFormat {
+	case "human":
+		printHumanDiff(w, d)
+	case "json":
+		if err := lib.WriteJSON(w, d); err != nil {
+			return err
+		}
+	case "markdown":
+		printMarkdownDiff(w, d)
+	default:
+		return fmt.Errorf("unknown format: %s", diffFormat)
+	}
+
+	cmd.Annotations = make(map[string]string)
+	cmd.Annotations[exitCodeAnnotationKey] = fmt.Sprintf("%d", d.ExitCode())
+	return nil
+}
+
+func printHumanDiff(w io.Writer, d lib.ReportDiff) {
+	_, _ = fmt.Fprintf(w, "New Matches: %s\n", gchalk.Red(fmt.Sprintf("%d", len(d.NewMatches))))
+	for _, m := range d.NewMatches {
+		_, _ = fmt.Fprintf(w, "    (%s) %s\n", strings.Join(m.MatchTypes, " "), m.Id)
+	}
+	_, _ = fmt.Fprintf(w, "\nResolved Matches: %s\n", gchalk.Green(fmt.Sprintf("%d", len(d.ResolvedMatches))))
+	for _, m := range d.ResolvedMatches {
+		_, _ = fmt.Fprintf(w, "    (%s) %s\n", strings.Join(m.MatchTypes, " "), m.Id)
+	}
+	_, _ = fmt.Fprintf(w, "\nChanged Matches: %s\n", gchalk.Yellow(fmt.Sprintf("%d", len(d.ChangedMatches))))
+	for _, c := range d.ChangedMatches {
+		changes := strings.TrimSpace(fmt.Sprintf("%s %s", gchalk.Red(prefixAll("+", c.AddedMatchTypes())), gchalk.Green(prefixAll("-", c.RemovedMatchTypes()))))
+		if len(changes) == 0 {
+			changes = strings.Join(c.After.MatchTypes, " ")
+		}
+		_, _ = fmt.Fprintf(w, "    (%s) %s\n", changes, c.After.Id)
+		if c.HashChanged() {
+			_, _ = fmt.Fprintf(w, "        %s\n", gchalk.Grey(fmt.Sprintf("hash %s -> %s", c.Before.Hash, c.After.Hash)))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\nNew Failures: %s\n", gchalk.Red(fmt.Sprintf("%d", len(d.NewFailures))))
+	for _, f := range d.NewFailures {
+		_, _ = fmt.Fprintf(w, "    %s\n        %s\n", f.Id, gchalk.Grey(strings.Join(f.Messages, "\n        ")))
+	}
+	_, _ = fmt.Fprintf(w, "\nResolved Failures: %s\n", gchalk.Green(fmt.Sprintf("%d", len(d.ResolvedFailures))))
+	for _, f := range d.ResolvedFailures {
+		_, _ = fmt.Fprintf(w, "    %s\n", f.Id)
+	}
+	_, _ = fmt.Fprintf(w, "\nChanged Failures: %s\n", gchalk.Yellow(fmt.Sprintf("%d", len(d.ChangedFailures))))
+	for _, c := range d.ChangedFailures {
+		_, _ = fmt.Fprintf(w, "    %s\n", c.After.Id)
+		for _, m := range c.AddedMessages() {
+			_, _ = fmt.Fprintf(w, "        %s\n", gchalk.Red("+"+m))
+		}
+		for _, m := range c.RemovedMessages() {
+			_, _ = fmt.Fprintf(w, "        %s\n", gchalk.Green("-"+m))
+		}
+	}
+}
+
+func printMarkdownDiff(w io.Writer, d lib.ReportDiff) {
+	_, _ = fmt.Fprintln(w, "# Scan Report Diff")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "| Change | Count |")
+	_, _ = fmt.Fprintln(w, "| --- | ---: |")
+	_, _ = fmt.Fprintf(w, "| New matches | %d |\n", len(d.NewMatches))
+	_, _ = fmt.Fprintf(w, "| Resolved matches | %d |\n", len(d.ResolvedMatches))
+	_, _ = fmt.Fprintf(w, "| Changed matches | %d |\n", len(d.ChangedMatches))
+	_, _ = fmt.Fprintf(w, "| New failures | %d |\n", len(d.NewFailures))
+	_, _ = fmt.Fprintf(w, "| Resolved failures | %d |\n", len(d.ResolvedFailures))
+	_, _ = fmt.Fprintf(w, "| Changed failures | %d |\n", len(d.ChangedFailures))
+
+	printMarkdownMatches(w, "New Matches", d.NewMatches)
+	printMarkdownMatches(w, "Resolved Matches", d.ResolvedMatches)
+	if len(d.ChangedMatches) > 0 {
+		_, _ = fmt.Fprintf(w, "\n## Changed Matches\n\n| Path | Added | Removed | Hash |\n| --- | --- | --- | --- |\n")
+		for _, c := range d.ChangedMatches {
+			hash := fmt.Sprintf("`%s`", c.After.Hash)
+			if c.HashChanged() {
+				hash = fmt.Sprintf("`%s` -> `%s`", c.Before.Hash, c.After.Hash)
+			}
+			_, _ = fmt.Fprintf(w, "| `%s` | %s | %s | %s |\n", c.After.Id, strings.Join(c.AddedMatchTypes(), ", "), strings.Join(c.RemovedMatchTypes(), ", "), hash)
+		}
+	}
+	printMarkdownFailures(w, "New Failures", d.NewFailures)
+	printMarkdownFailures(w, "Resolved Failures", d.ResolvedFailures)
+	if len(d.ChangedFailures) > 0 {
+		_, _ = fmt.Fprintf(w, "\n## Changed Failures\n\n| Path | Added | Removed |\n| --- | --- | --- |\n")
+		for _, c := range d.ChangedFailures {
+			_, _ = fmt.Fprintf(w, "| `%s` | %s | %s |\n", c.After.Id, markdownMessages(c.AddedMessages()), markdownMessages(c.RemovedMessages()))
+		}
+	}
+}
+
+func printMarkdownMatches(w io.Writer, title string, matches []lib.ReportMatch) {
+	if len(matches) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "\n## %s\n\n| Path | Match Types | Hash |\n| --- | --- | --- |\n", title)
+	for _, m := range matches {
+		_, _ = fmt.Fprintf(w, "| `%s` | %s | `%s` |\n", m.Id, strings.Join(m.MatchTypes, ", "), m.Hash)
+	}
+}
+
+func printMarkdownFailures(w io.Writer, title string, failures []lib.ReportFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "\n## %s\n\n| Path | Messages |\n| --- | --- |\n", title)
+	for _, f := range failures {
+		_, _ = fmt.Fprintf(w, "| `%s` | %s |\n", f.Id, markdownMessages(f.Messages))
+	}
+}
+
+func markdownMessages(messages []string) string {
+	return strings.ReplaceAll(strings.Join(messages, "<br>"), "|", "\\|")
+}
+
+func prefixAll(prefix string, values []string) string {
+	prefixed := make([]string, len(values))
+	for i, v := range values {
+		prefixed[i] = prefix + v
+	}
+	return strings.Join(prefixed, " ")
+}
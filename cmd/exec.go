@@ -24,7 +24,10 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/thecodeteam/goodbye"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var (
@@ -32,22 +35,55 @@ var (
 		Use:   "log4shell-scanner [flags]",
 		Short: "log4shell-scanner recursively scans a filesystem looking for affected jars.",
 		Long: `log4shell-scanner recursively scans a filesystem looking for jars with a has that 
-indicates they can be exploited.  The scanner with also search within jar, zip, and gzip archive.`,
-		Example:               `log4shell-scanner --root=.`,
+indicates they can be exploited.  The scanner with also search within jar, zip, and gzip archive.
+
+Exit codes:
+  0  no match or failure violated the policy
+  1  the scan could not be run
+  2  one or more matches violated the policy (see --fail-on and --fail-on-severity)
+  4  more scan failures than allowed (see --ignore-failures-matching and --max-failures)
+  6  both matches and failures violated the policy`,
+		Example: `  log4shell-scanner --root=.
+  log4shell-scanner --config=scanner.yaml --profile=ci
+  find / -name '*.jar' -print0 | log4shell-scanner --paths-from=-`,
 		PreRunE:               pre,
 		RunE:                  run,
 		DisableFlagsInUseLine: true,
 	}
-	roots           []string
-	jarHashesFile   string
-	classHashesFile string
-	printVersion    bool
-	verbosity       int
-	classes         []string
-	includeGlobs    []string
-	excludeGlobs    []string
-	jars            []string
-	scanner         lib.Scanner
+	roots            []string
+	pathsFrom        string
+	jarHashesFile    string
+	classHashesFile  string
+	printVersion     bool
+	verbosity        int
+	classes          []string
+	includeGlobs     []string
+	excludeGlobs     []string
+	jars             []string
+	osvPaths         []string
+	repositories     bool
+	oneFileSystem    bool
+	skipFsTypes      []string
+	maxDepth         int
+	noIgnoreFiles    bool
+	suppressionsFile string
+	contentRulesFile string
+	detectorNames    []string
+	springHashesFile string
+	textHashesFile   string
+	suppressions     lib.Suppressions
+	failOn           []string
+	failOnSeverity   string
+	ignoreFailures   []string
+	maxFailures      int
+	policy           lib.Policy
+	reportFile       string
+	minFileSize      string
+	maxFileSize      string
+	modifiedSince    string
+	modifiedBefore   string
+	onlyArchives     bool
+	scanner          lib.Scanner
 )
 
 const (
@@ -59,24 +95,61 @@ func init() {
 	rootCmd.SetVersionTemplate(version.Print())
 	rootCmd.Flags().StringSliceVarP(&roots, "root", "r", []string{workingDir}, "Root directory to scan (repeatable)")
 	_ = rootCmd.MarkFlagDirname("root")
-	rootCmd.Flags().StringSliceVar(&jars, "jars", []string{"log4j-core-/2.0-beta9/2.16.0"}, "Jar name and semver range to match (repeatable)")
-	rootCmd.Flags().StringVar(&jarHashesFile, "jar-hashes", "", "File containing SHA256 hashes of jars to match")
+	rootCmd.Flags().StringVar(&pathsFrom, "paths-from", "", "File containing newline or NUL delimited paths to scan instead of walking the roots ('-' for stdin)")
+	_ = rootCmd.MarkFlagFilename("paths-from")
+	rootCmd.Flags().StringSliceVar(&jars, "jars", []string{"log4j-core-/2.0-beta9/2.16.0"}, "Jar artifactId and inclusive maven version range to match, ie: log4j-core/2.0-beta9/2.16.0 (repeatable)")
+	rootCmd.Flags().StringSliceVar(&osvPaths, "osv", []string{}, "OSV JSON record, or directory of records such as the Maven ecosystem export, of advisories to match jar coordinates against (repeatable)")
+	rootCmd.Flags().BoolVar(&repositories, "repositories", false, "Match jars within maven local repositories (.m2/repository) and gradle caches (modules-2/files-2.1) by the coordinates of their path")
+	rootCmd.Flags().StringVar(&jarHashesFile, "jar-hashes", "", "File containing MD5, SHA1, SHA256 or SHA512 hashes of jars to match")
 	_ = rootCmd.MarkFlagFilename("jar-hashes")
 	rootCmd.Flags().StringSliceVar(&classes, "classes", []string{"JndiLookup"}, "Classes to match (repeatable)")
-	rootCmd.Flags().StringVar(&classHashesFile, "class-hashes", "", "File containing SHA256 hashes of classes to match")
+	rootCmd.Flags().StringVar(&classHashesFile, "class-hashes", "", "File containing MD5, SHA1, SHA256 or SHA512 hashes of classes to match")
 	_ = rootCmd.MarkFlagFilename("class-hashes")
+	rootCmd.Flags().StringVar(&contentRulesFile, "content-rules", "", "File containing rules matching strings, hex byte sequences or regexes in files and archive entries selected by path globs")
+	_ = rootCmd.MarkFlagFilename("content-rules", "yaml", "yml")
+	rootCmd.Flags().StringSliceVar(&detectorNames, "detectors", lib.DefaultDetectors, fmt.Sprintf("Detectors to scan with, any of %s (repeatable)", strings.Join(lib.DetectorNames(), ",")))
+	rootCmd.Flags().StringVar(&springHashesFile, "spring-fixed-class-hashes", "", "File containing hashes of CachedIntrospectionResults classes from spring-beans versions fixed for Spring4Shell, ie: from hashdb build --classes=CachedIntrospectionResults")
+	_ = rootCmd.MarkFlagFilename("spring-fixed-class-hashes")
+	rootCmd.Flags().StringVar(&textHashesFile, "text4shell-class-hashes", "", "File containing hashes of StringLookupFactory and ScriptStringLookup classes from commons-text versions affected by Text4Shell, ie: from hashdb build --classes=StringLookupFactory,ScriptStringLookup")
+	_ = rootCmd.MarkFlagFilename("text4shell-class-hashes")
+	rootCmd.Flags().StringVar(&suppressionsFile, "suppressions", "", "File containing justified, expiring suppressions of accepted matches")
+	_ = rootCmd.MarkFlagFilename("suppressions", "yaml", "yml")
+	rootCmd.Flags().StringSliceVar(&failOn, "fail-on", lib.DefaultFailOn, fmt.Sprintf("Match types that fail the scan, one of %s (repeatable)", strings.ToLower(strings.Join(lib.MatchTypeStrings(), ","))))
+	rootCmd.Flags().StringVar(&failOnSeverity, "fail-on-severity", lib.Info.String(), fmt.Sprintf("Minimum severity of a match that fails the scan, one of %s", strings.Join(lib.SeverityStrings(), ",")))
+	rootCmd.Flags().StringArrayVar(&ignoreFailures, "ignore-failures-matching", []string{}, "Regex matched against 'file: message' of scan failures that do not fail the scan (repeatable)")
+	rootCmd.Flags().IntVar(&maxFailures, "max-failures", 0, "Maximum number of scan failures that do not fail the scan")
+	rootCmd.Flags().StringVar(&reportFile, "report", "", "File to write a JSON report of the scan to")
+	_ = rootCmd.MarkFlagFilename("report", "json")
 	rootCmd.Flags().StringSliceVar(&includeGlobs, "include-globs", []string{"**/**"}, "Globs that indicate which paths to include in the scan (repeatable)")
 	rootCmd.Flags().StringSliceVar(&excludeGlobs, "exclude-globs", []string{"**/.git/**", "**/.runtime/**", "**/node_modules/**"}, "Globs that indicate which paths to exclude in the scan (repeatable)")
+	rootCmd.Flags().BoolVar(&oneFileSystem, "one-file-system", false, "Do not descend into directories on a different filesystem than the root")
+	rootCmd.Flags().StringSliceVar(&skipFsTypes, "skip-fstypes", []string{"proc", "sysfs", "devtmpfs"}, "Filesystem types whose mounts are not scanned, ie: nfs,cifs,fuse.sshfs (repeatable)")
+	rootCmd.Flags().IntVar(&maxDepth, "max-depth", -1, "Maximum directory depth to descend below each root (-1 for unlimited)")
+	rootCmd.Flags().BoolVar(&noIgnoreFiles, "no-ignore-files", false, fmt.Sprintf("Do not honour %s files found while walking", lib.IgnoreFileName))
+	rootCmd.Flags().StringVar(&minFileSize, "min-file-size", "", "Minimum size of files to scan, ie: 1K")
+	rootCmd.Flags().StringVar(&maxFileSize, "max-file-size", "", "Maximum size of files to scan, ie: 500MB")
+	rootCmd.Flags().StringVar(&modifiedSince, "modified-since", "", "Only scan files modified at or after this time (RFC3339, 2006-01-02, or a duration like 30d)")
+	rootCmd.Flags().StringVar(&modifiedBefore, "modified-before", "", "Only scan files modified before this time (RFC3339, 2006-01-02, or a duration like 30d)")
+	rootCmd.Flags().BoolVar(&onlyArchives, "only-archives", false, "Only scan files with an archive extension or magic number, and the configuration files the config detector inspects")
 	rootCmd.Flags().CountVarP(&verbosity, "verbose", "v", "Verbose logging")
 	rootCmd.Flags().BoolVar(&printVersion, "version", false, "Print version")
 	lib.AddProfileFlags(rootCmd)
+	addConfigFlags(rootCmd)
+	rootCmd.AddCommand(newConfigCmd(rootCmd.LocalFlags()))
+	rootCmd.AddCommand(newBaselineCmd(rootCmd.LocalFlags()))
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newHashdbCmd())
+	rootCmd.AddCommand(newLogsCmd())
 }
 
-func pre(_ *cobra.Command, _ []string) error {
+func pre(cmd *cobra.Command, args []string) error {
 	if printVersion {
 		_, _ = fmt.Fprintf(os.Stdout, "%s\n", version.Print())
 		os.Exit(0)
 	}
+	if err := loadConfig(cmd, args); err != nil {
+		return err
+	}
 
 	globMatcher, err := lib.NewGlobMatcher(includeGlobs, excludeGlobs)
 	if err != nil {
@@ -88,7 +161,6 @@ func pre(_ *cobra.Command, _ []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load class hashes: %v", err)
 	}
-	classScanner := lib.NewClassScanner(classNameMatcher, classHashMatcher)
 
 	jarNameMatcher := lib.NewJarNameMatcher()
 	err = jarNameMatcher.AddMatchers(jars...)
@@ -99,34 +171,141 @@ func pre(_ *cobra.Command, _ []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load jar hashes: %v", err)
 	}
-	jarScanner := lib.NewJarScanner(jarNameMatcher, jarHashMatcher)
+	advisories, err := lib.LoadOSVAdvisories(osvPaths...)
+	if err != nil {
+		return fmt.Errorf("failed to load advisories: %v", err)
+	}
+
+	walkOptions := lib.WalkOptions{
+		OneFileSystem: oneFileSystem,
+		SkipFsTypes:   skipFsTypes,
+		MaxDepth:      maxDepth,
+		IgnoreFiles:   !noIgnoreFiles,
+	}
+	fileFilter, err := newFileFilter()
+	if err != nil {
+		return err
+	}
+	contentRules := lib.ContentRules{}
+	if len(contentRulesFile) > 0 {
+		contentRules, err = lib.LoadContentRules(contentRulesFile)
+		if err != nil {
+			return fmt.Errorf("failed to load content rules: %v", err)
+		}
+	}
+	springFixedClassHashMatcher, err := lib.NewHashMatcherFromFile(springHashesFile, "")
+	if err != nil {
+		return fmt.Errorf("failed to load spring class hashes: %v", err)
+	}
+	textLookupClassHashMatcher, err := lib.NewHashMatcherFromFile(textHashesFile, "")
+	if err != nil {
+		return fmt.Errorf("failed to load commons-text class hashes: %v", err)
+	}
+	detectors, err := lib.NewDetectors(detectorNames, lib.DetectorOptions{
+		ClassNameMatcher:            classNameMatcher,
+		ClassHashMatcher:            classHashMatcher,
+		JarNameMatcher:              jarNameMatcher,
+		JarHashMatcher:              jarHashMatcher,
+		AdvisoryMatcher:             lib.NewAdvisoryMatcher(advisories...),
+		Repositories:                repositories,
+		ContentRuleMatcher:          lib.NewContentRuleMatcher(contentRules),
+		SpringFixedClassHashMatcher: springFixedClassHashMatcher,
+		TextLookupClassHashMatcher:  textLookupClassHashMatcher,
+	})
+	if err != nil {
+		return err
+	}
+	scanner = lib.NewScanner(detectors, globMatcher, walkOptions, fileFilter, verbosity)
+
+	if len(suppressionsFile) > 0 {
+		suppressions, err = lib.LoadSuppressions(suppressionsFile)
+		if err != nil {
+			return fmt.Errorf("failed to load suppressions: %v", err)
+		}
+	}
 
-	scanner = lib.NewScanner(classScanner, jarScanner, globMatcher, verbosity)
+	policy, err = lib.NewPolicy(failOn, failOnSeverity, ignoreFailures, maxFailures)
+	if err != nil {
+		return fmt.Errorf("invalid policy: %v", err)
+	}
 	return nil
 }
 
+func scan() (lib.ScanResult, error) {
+	if len(pathsFrom) > 0 {
+		paths, err := lib.ReadPathsFromFile(pathsFrom)
+		if err != nil {
+			return lib.ScanResult{}, fmt.Errorf("failed to read paths from %s: %v", pathsFrom, err)
+		}
+		return scanner.ScanPaths(paths...)
+	}
+	return scanner.Scan(roots...)
+}
+
+func newFileFilter() (lib.FileFilter, error) {
+	minSize, err := lib.ParseSize(minFileSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse min-file-size: %v", err)
+	}
+	maxSize, err := lib.ParseSize(maxFileSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse max-file-size: %v", err)
+	}
+	now := time.Now()
+	since, err := lib.ParseTime(modifiedSince, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse modified-since: %v", err)
+	}
+	before, err := lib.ParseTime(modifiedBefore, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse modified-before: %v", err)
+	}
+	return lib.NewFileFilter(minSize, maxSize, since, before, onlyArchives), nil
+}
+
 func run(cmd *cobra.Command, _ []string) error {
 	err := lib.StartProfiling()
 	if err != nil {
 		return err
 	}
 
-	result, err := scanner.Scan(roots...)
+	result, err := scan()
 	if err != nil {
 		return err
 	}
+	if len(suppressionsFile) > 0 {
+		result.ApplySuppressions(suppressions, time.Now())
+	}
 	fmt.Printf("%s\nTotal Files Scanned: %d\n", lib.ResetLine, result.GetTotalFilesScanned())
+	fmt.Printf("Total Files Skipped: %d\n", result.GetTotalFilesSkipped())
+	fmt.Printf("Total Files Filtered: %d\n", result.GetTotalFilesFiltered())
 	fmt.Printf("\nTotal Matched Files: %d\n", result.GetTotalFilesMatched())
 	fmt.Printf("    Content Matches: %s\n", gchalk.Blue(fmt.Sprintf("%d", result.GetMatchCountByType(lib.Content))))
 	fmt.Printf("    Class Name Matches: %s\n", gchalk.Green(fmt.Sprintf("%d", result.GetMatchCountByType(lib.ClassName))))
 	fmt.Printf("    Class Hash Matches: %s\n", gchalk.Red(fmt.Sprintf("%d", result.GetMatchCountByType(lib.ClassHash))))
 	fmt.Printf("    Jar Name Matches: %s\n", gchalk.Cyan(fmt.Sprintf("%d", result.GetMatchCountByType(lib.JarName))))
 	fmt.Printf("    Jar Hash Matches: %s\n", gchalk.Yellow(fmt.Sprintf("%d", result.GetMatchCountByType(lib.JarHash))))
+	if len(osvPaths) > 0 {
+		fmt.Printf("    Jar Advisory Matches: %s\n", gchalk.Magenta(fmt.Sprintf("%d", result.GetMatchCountByType(lib.JarAdvisory))))
+	}
+	fmt.Printf("    Config Lookup Matches: %s\n", gchalk.BrightRed(fmt.Sprintf("%d", result.GetMatchCountByType(lib.ConfigLookup))))
+	if len(contentRulesFile) > 0 {
+		fmt.Printf("    Content Pattern Matches: %s\n", gchalk.BrightMagenta(fmt.Sprintf("%d", result.GetMatchCountByType(lib.ContentPattern))))
+	}
+	if isDetectorEnabled(lib.Spring4ShellDetectorName) {
+		fmt.Printf("    Spring4Shell Matches: %s\n", gchalk.BrightYellow(fmt.Sprintf("%d", result.GetMatchCountByType(lib.Spring4shell))))
+	}
+	if isDetectorEnabled(lib.Text4ShellDetectorName) {
+		fmt.Printf("    Text4Shell Matches: %s\n", gchalk.BrightCyan(fmt.Sprintf("%d", result.GetMatchCountByType(lib.Text4shell))))
+		fmt.Printf("    Text4Shell Class Matches: %s\n", gchalk.Cyan(fmt.Sprintf("%d", result.GetMatchCountByType(lib.Text4shellClass))))
+	}
+	if isDetectorEnabled(lib.LoggingDetectorName) {
+		fmt.Printf("    Logback Matches: %s\n", gchalk.BrightBlue(fmt.Sprintf("%d", result.GetMatchCountByType(lib.Logback))))
+		fmt.Printf("    Log4j 1.x Matches: %s\n", gchalk.Blue(fmt.Sprintf("%d", result.GetMatchCountByType(lib.Log4j1))))
+	}
 	fmt.Println("\nMatched Files: ")
 
-	exitCode := 0
 	if result.GetTotalFilesMatched() > 0 {
-		exitCode += 2
 		for _, m := range result.GetMatches() {
 			fmt.Printf("    %s\n", m)
 		}
@@ -134,18 +313,89 @@ func run(cmd *cobra.Command, _ []string) error {
 		fmt.Println("    NONE")
 	}
 
+	fmt.Println("\nMitigations: ")
+	mitigations := result.GetMitigations()
+	enabled := false
+	for _, m := range mitigations {
+		fmt.Printf("    %s\n", m)
+		enabled = enabled || m.Enabled()
+	}
+	if !enabled && result.GetTotalFilesMatched() > 0 && isDetectorEnabled(lib.ConfigDetectorName) {
+		fmt.Printf("    %s\n", gchalk.Yellow("NONE, formatMsgNoLookups is not enabled by any scanned component properties, systemd unit or script"))
+	} else if len(mitigations) == 0 {
+		fmt.Println("    NONE")
+	}
+
+	if isDetectorEnabled(lib.LoggingDetectorName) {
+		printLoggingInventory(result)
+	}
+
+	if isDetectorEnabled(lib.JavaRuntimeDetectorName) {
+		printJavaRuntimes(result)
+	}
+
+	if repositories {
+		repositoryMatches := result.GetRepositoryMatches()
+		fmt.Printf("\nRepositories With Matches: %d\n", len(repositoryMatches))
+		for _, r := range repositoryMatches {
+			fmt.Printf("    %s (%s)\n", r.Root, r.Layout)
+			for _, m := range r.Matches {
+				fmt.Printf("        %s %s\n", m.Repository().Coordinates, gchalk.Grey(fmt.Sprintf("(%s)", strings.Join(lib.MatchTypeNames(m.MatchTypes()), " "))))
+			}
+		}
+	}
+
 	fmt.Printf("\nTotal Scan Failures: %d\n", result.GetTotalScanFailures())
 	fmt.Println("\nFailed Files: ")
 	if result.GetTotalScanFailures() > 0 {
-		exitCode += 4
 		for _, m := range result.GetFailures() {
 			fmt.Printf("    %s\n", m)
 		}
 	} else {
 		fmt.Println("    NONE")
 	}
+
+	if len(suppressionsFile) > 0 {
+		suppressedMatches := result.GetSuppressedMatches()
+		fmt.Printf("\nTotal Suppressed Matches: %d\n", len(suppressedMatches))
+		for _, m := range suppressedMatches {
+			fmt.Printf("    %s\n", m)
+		}
+		expiredSuppressions := result.GetExpiredSuppressions()
+		fmt.Printf("\nTotal Expired Suppressions: %d\n", len(expiredSuppressions))
+		for _, e := range expiredSuppressions {
+			fmt.Printf("    %s\n", gchalk.Yellow(e.String()))
+		}
+	}
+
+	if skippedMounts := result.GetSkippedMounts(); len(skippedMounts) > 0 {
+		fmt.Printf("\nSkipped Mount Points: %d\n", len(skippedMounts))
+		for _, m := range skippedMounts {
+			fmt.Printf("    %s\n", m)
+		}
+	}
+
+	if ignoreFiles := result.GetIgnoreFiles(); len(ignoreFiles) > 0 {
+		fmt.Printf("\nIgnore Files Used: %d\n", len(ignoreFiles))
+		for _, f := range ignoreFiles {
+			fmt.Printf("    %s\n", f)
+		}
+	}
+
+	policyResult := policy.Evaluate(result)
+	fmt.Println("\nPolicy Violations: ")
+	fmt.Printf("    Matches: %d\n", len(policyResult.ViolatingMatches))
+	fmt.Printf("    Failures: %d (ignored: %d, allowed: %d)\n", len(policyResult.ViolatingFailures), len(policyResult.IgnoredFailures), maxFailures)
+	fmt.Printf("    Exit Code: %d\n", policyResult.ExitCode)
+
+	if len(reportFile) > 0 {
+		report := lib.NewReport(result, policyResult.ExitCode, version.Version, time.Now())
+		if err := report.WriteFile(reportFile); err != nil {
+			return fmt.Errorf("failed to write report: %v", err)
+		}
+	}
 	cmd.Annotations = make(map[string]string)
-	cmd.Annotations[exitCodeAnnotationKey] = fmt.Sprintf("%d", exitCode)
+	cmd.Annotations[exitCodeAnnotationKey] = fmt.Sprintf("%d", policyResult.ExitCode)
 	return nil
 }
 
@@ -154,16 +404,81 @@ func Execute() {
 	defer goodbye.Exit(ctx, -1)
 	goodbye.Notify(ctx)
 
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	executedCmd, err := rootCmd.ExecuteC()
+	if err != nil {
+		os.Exit(lib.ExitCodeError)
 	}
-	if exitCodeString, ok := rootCmd.Annotations[exitCodeAnnotationKey]; ok {
+	if exitCodeString, ok := executedCmd.Annotations[exitCodeAnnotationKey]; ok {
 		exitCode, err := strconv.ParseInt(exitCodeString, 10, 8)
 		if err != nil {
-			goodbye.Exit(ctx, 1)
+			goodbye.Exit(ctx, lib.ExitCodeError)
 		}
 		goodbye.Exit(ctx, int(exitCode))
 	} else {
-		goodbye.Exit(ctx, 0)
+		goodbye.Exit(ctx, lib.ExitCodeOk)
+	}
+}
+
+func isDetectorEnabled(name string) bool {
+	for _, d := range detectorNames {
+		if strings.EqualFold(strings.TrimSpace(d), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func printLoggingInventory(result lib.ScanResult) {
+	host, _ := os.Hostname()
+	inventory := result.GetInventory(lib.LoggingComponentKind)
+	fmt.Printf("\nLogging Frameworks on %s: %d\n", host, len(inventory))
+	for _, i := range inventory {
+		var issues []string
+		for _, a := range i.Advisories {
+			issues = append(issues, a.Id)
+		}
+		for m := range result.GetMatchesForFileId(i.FileId) {
+			if m != lib.Content && m != lib.Logback && m != lib.Log4j1 {
+				issues = append(issues, m.String())
+			}
+		}
+		sort.Strings(issues)
+		status := gchalk.Green("no matches")
+		if len(issues) > 0 {
+			status = gchalk.Yellow(strings.Join(issues, ", "))
+		}
+		fmt.Printf("    %-40s %s %s\n", i.Component, gchalk.Grey(i.FileId), status)
+	}
+}
+
+func printJavaRuntimes(result lib.ScanResult) {
+	runtimes := result.GetInventory(lib.JavaRuntimeComponentKind)
+	matches := map[string][]string{}
+	var uncorrelated []string
+	for _, m := range result.GetMatches() {
+		if r, ok := result.GetRuntime(m); ok {
+			matches[r.Home] = append(matches[r.Home], m.FileId())
+		} else {
+			uncorrelated = append(uncorrelated, m.FileId())
+		}
+	}
+	fmt.Printf("\nJava Runtimes: %d\n", len(runtimes))
+	for _, r := range runtimes {
+		codebase := gchalk.Grey("trustURLCodebase default unknown")
+		if trusts, known := lib.JavaTrustsURLCodebase(r.Version); known && trusts {
+			codebase = gchalk.Yellow("trustURLCodebase=true by default, LDAP remote class loading is possible")
+		} else if known {
+			codebase = gchalk.Green("trustURLCodebase=false by default")
+		}
+		fmt.Printf("    %s %s %s\n", r.Component, gchalk.Grey(r.Home), codebase)
+		for _, m := range matches[r.Home] {
+			fmt.Printf("        %s\n", m)
+		}
+	}
+	if len(uncorrelated) > 0 {
+		fmt.Printf("    %s\n", gchalk.Yellow("No runtime found for:"))
+		for _, m := range uncorrelated {
+			fmt.Printf("        %s\n", m)
+		}
 	}
 }
@@ -0,0 +1,94 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/kadaan/log4shell-scanner/lib"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var (
+	hashdbCmd = &cobra.Command{
+		Use:   "hashdb",
+		Short: "Manage hash databases used by --jar-hashes and --class-hashes.",
+	}
+	hashdbBuildCmd = &cobra.Command{
+		Use:   "build [flags]",
+		Short: "Build annotated jar and class hash files from a directory of reference artifacts.",
+		Long: `Walk a directory of reference artifacts, such as a local maven repository mirror, and write the
+hash of every jar, and of the selected classes within them, annotated with the artifact coordinates,
+version and CVEs.  The files can be passed to --jar-hashes and --class-hashes.`,
+		Example:               `  log4shell-scanner hashdb build --root=~/.m2/repository/org/apache/logging/log4j/log4j-core --cve=CVE-2021-44228`,
+		Args:                  cobra.NoArgs,
+		RunE:                  buildHashDatabase,
+		DisableFlagsInUseLine: true,
+	}
+	hashdbRoots        []string
+	hashdbClasses      []string
+	hashdbCVEs         []string
+	hashdbExcludeGlobs []string
+	hashdbJarOutput    string
+	hashdbClassOutput  string
+	hashdbVerbosity    int
+)
+
+func newHashdbCmd() *cobra.Command {
+	workingDir, _ := os.Getwd()
+	hashdbBuildCmd.Flags().StringSliceVarP(&hashdbRoots, "root", "r", []string{workingDir}, "Root directory of reference artifacts (repeatable)")
+	_ = hashdbBuildCmd.MarkFlagDirname("root")
+	hashdbBuildCmd.Flags().StringSliceVar(&hashdbClasses, "classes", []string{"JndiLookup"}, "Classes to hash (repeatable)")
+	hashdbBuildCmd.Flags().StringSliceVar(&hashdbCVEs, "cve", []string{}, "CVE to annotate every entry with (repeatable)")
+	hashdbBuildCmd.Flags().StringSliceVar(&hashdbExcludeGlobs, "exclude-globs", []string{"**/.git/**"}, "Globs that indicate which paths to exclude (repeatable)")
+	hashdbBuildCmd.Flags().StringVar(&hashdbJarOutput, "jar-output", "jar-hashes.txt", "File to write jar hashes to")
+	hashdbBuildCmd.Flags().StringVar(&hashdbClassOutput, "class-output", "class-hashes.txt", "File to write class hashes to")
+	hashdbBuildCmd.Flags().CountVarP(&hashdbVerbosity, "verbose", "v", "Verbose logging")
+	hashdbCmd.AddCommand(hashdbBuildCmd)
+	return hashdbCmd
+}
+
+func buildHashDatabase(_ *cobra.Command, _ []string) error {
+	globMatcher, err := lib.NewGlobMatcher([]string{"**/**"}, hashdbExcludeGlobs)
+	if err != nil {
+		return err
+	}
+	builder := lib.NewHashDatabaseBuilder(lib.NewClassNameMatcher(hashdbClasses), globMatcher, hashdbCVEs, hashdbVerbosity)
+	jars, classes, err := builder.Build(hashdbRoots...)
+	if err != nil {
+		return err
+	}
+	if err := writeHashDatabase(hashdbJarOutput, jars); err != nil {
+		return fmt.Errorf("failed to write jar hashes: %v", err)
+	}
+	if err := writeHashDatabase(hashdbClassOutput, classes); err != nil {
+		return fmt.Errorf("failed to write class hashes: %v", err)
+	}
+	fmt.Printf("%s\nWrote %d jar hashes to %s\n", lib.ResetLine, len(jars), hashdbJarOutput)
+	fmt.Printf("Wrote %d class hashes to %s\n", len(classes), hashdbClassOutput)
+	return nil
+}
+
+func writeHashDatabase(file string, entries []lib.HashDatabaseEntry) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(f)
+	return lib.WriteHashDatabase(f, entries)
+}
@@ -0,0 +1,131 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/jwalton/gchalk"
+	"github.com/kadaan/log4shell-scanner/lib"
+	"github.com/spf13/cobra"
+	"os"
+	"sort"
+	"strings"
+)
+
+var (
+	logsCmd = &cobra.Command{
+		Use:   "logs [flags] PATH...",
+		Short: "Scan log files for log4shell exploitation attempts.",
+		Long: `Scan plain and gzip compressed log files for ${jndi:...} payloads, including those obfuscated with
+nested lookups, such as ${${lower:j}ndi:...} or ${${::-j}ndi:...}, and those which are URL or base64
+encoded.  Files given as arguments are always scanned, while directories are walked for files whose
+base name matches --log-globs.
+
+Exit codes:
+  0  no payloads found
+  1  the logs could not be scanned
+  2  payloads found
+  4  log files failed to scan
+  6  both payloads found and log files failed to scan`,
+		Example: `  log4shell-scanner logs /var/log/nginx /opt/tomcat/logs/catalina.out
+  log4shell-scanner logs /var/log --report=attempts.json`,
+		Args:                  cobra.MinimumNArgs(1),
+		RunE:                  scanLogs,
+		DisableFlagsInUseLine: true,
+	}
+	logGlobs        []string
+	logExcludeGlobs []string
+	logReportFile   string
+	logVerbosity    int
+)
+
+func newLogsCmd() *cobra.Command {
+	logsCmd.Flags().StringSliceVar(&logGlobs, "log-globs", lib.DefaultLogGlobs, "Globs matched against the base name of files found in directories to select logs to scan (repeatable)")
+	logsCmd.Flags().StringSliceVar(&logExcludeGlobs, "exclude-globs", []string{"**/.git/**"}, "Globs that indicate which paths to exclude (repeatable)")
+	logsCmd.Flags().StringVar(&logReportFile, "report", "", "File to write a JSON report of the payloads found to")
+	_ = logsCmd.MarkFlagFilename("report", "json")
+	logsCmd.Flags().CountVarP(&logVerbosity, "verbose", "v", "Verbose logging")
+	return logsCmd
+}
+
+func scanLogs(cmd *cobra.Command, args []string) error {
+	globMatcher, err := lib.NewGlobMatcher([]string{"**/**"}, logExcludeGlobs)
+	if err != nil {
+		return err
+	}
+	logScanner, err := lib.NewLogScanner(globMatcher, logGlobs, logVerbosity)
+	if err != nil {
+		return err
+	}
+	result, err := logScanner.Scan(args...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\nTotal Log Files Scanned: %d\n", lib.ResetLine, result.FilesScanned)
+	fmt.Printf("\nTotal Payloads Found: %d\n", len(result.Findings))
+	for _, f := range result.Findings {
+		details := []string{}
+		if f.Obfuscated {
+			details = append(details, "obfuscated")
+		}
+		details = append(details, f.Encodings...)
+		if callback := f.Callback(); len(callback) > 0 {
+			details = append(details, fmt.Sprintf("callback: %s", callback))
+		}
+		fmt.Printf("    %s %s\n", gchalk.Red(f.String()), gchalk.Grey(fmt.Sprintf("[%s]", strings.Join(details, ", "))))
+	}
+
+	callbacks := result.Callbacks()
+	fmt.Printf("\nCallback Hosts: %d\n", len(callbacks))
+	hosts := make([]string, 0, len(callbacks))
+	for h := range callbacks {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+	for _, h := range hosts {
+		fmt.Printf("    %s %s\n", h, gchalk.Grey(fmt.Sprintf("(%d)", callbacks[h])))
+	}
+
+	fmt.Printf("\nTotal Scan Failures: %d\n", len(result.Failures))
+	for _, f := range result.Failures {
+		fmt.Printf("    %s\n        %s\n", f.Id, gchalk.Grey(strings.Join(f.Messages, "\n        ")))
+	}
+
+	if len(logReportFile) > 0 {
+		f, err := os.Create(logReportFile)
+		if err != nil {
+			return fmt.Errorf("failed to write report: %v", err)
+		}
+		defer func(file *os.File) {
+			_ = file.Close()
+		}(f)
+		if err := lib.WriteJSON(f, result); err != nil {
+			return fmt.Errorf("failed to write report: %v", err)
+		}
+	}
+
+	exitCode := lib.ExitCodeOk
+	if len(result.Findings) > 0 {
+		exitCode |= lib.ExitCodeMatches
+	}
+	if len(result.Failures) > 0 {
+		exitCode |= lib.ExitCodeFailures
+	}
+	cmd.Annotations = make(map[string]string)
+	cmd.Annotations[exitCodeAnnotationKey] = fmt.Sprintf("%d", exitCode)
+	return nil
+}
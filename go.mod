@@ -5,20 +5,21 @@ go 1.17
 replace github.com/Sirupsen/logrus => github.com/sirupsen/logrus v1.8.1
 
 require (
-	github.com/Masterminds/semver v1.5.0
+	github.com/BurntSushi/toml v1.2.1
 	github.com/aquilax/truncate v1.0.0
 	github.com/bmatcuk/doublestar/v4 v4.0.2
 	github.com/h2non/filetype v1.1.3
 	github.com/jwalton/gchalk v1.2.1
 	github.com/mitchellh/go-homedir v1.0.0
 	github.com/spf13/cobra v1.2.1
+	github.com/spf13/pflag v1.0.5
 	github.com/thecodeteam/goodbye v0.0.0-20170927022442-a83968bda2d3
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/jwalton/go-supportscolor v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
 	golang.org/x/sys v0.0.0-20211004093028-2c5d950f24ef // indirect
 	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211 // indirect
 )
@@ -20,7 +20,9 @@ import (
 	"strings"
 )
 
+// ClassScanner is the class detector, which matches class files within archives by name and hash.
 type ClassScanner struct {
+	BaseDetector
 	classNameMatcher ClassNameMatcher
 	classHashMatcher HashMatcher
 }
@@ -32,28 +34,31 @@ func NewClassScanner(classNameMatcher ClassNameMatcher, classHashMatcher HashMat
 	}
 }
 
-func (s ClassScanner) Scan(contentFile ContentFile) ([]MatchType, error) {
-	if strings.HasSuffix(contentFile.Name(), ".class") {
-		basename := filepath.Base(contentFile.Name())
+func (s ClassScanner) Name() string {
+	return ClassDetectorName
+}
+
+func (s ClassScanner) Accepts(entry Entry) bool {
+	return len(entry.Archive()) > 0 && strings.HasSuffix(entry.Name(), ".class")
+}
 
-		var err error
-		classNameMatch := false
-		classNameMatch, err = s.classNameMatcher.IsMatch(basename)
-		if err != nil {
-			return []MatchType{}, err
-		}
-		hash, err := contentFile.Reader().Hash()
-		if err != nil {
-			return []MatchType{}, err
-		}
-		classHashMatch := s.classHashMatcher.IsHashMatch(hash)
-		if classNameMatch && classHashMatch {
-			return []MatchType{ClassName, ClassHash}, nil
-		} else if classNameMatch {
-			return []MatchType{ClassName}, nil
-		} else if classHashMatch {
-			return []MatchType{ClassHash}, nil
-		}
+func (s ClassScanner) InspectFile(entry Entry) (Finding, error) {
+	basename := filepath.Base(entry.Name())
+	classNameMatch, err := s.classNameMatcher.IsMatch(basename)
+	if err != nil {
+		return Finding{}, err
+	}
+	digests, err := entry.Hashes()
+	if err != nil {
+		return Finding{}, err
+	}
+	hashEntry, classHashMatch := s.classHashMatcher.GetHashMatch(digests)
+	if classNameMatch && classHashMatch {
+		return Finding{MatchTypes: []MatchType{ClassName, ClassHash}, HashEntry: &hashEntry}, nil
+	} else if classNameMatch {
+		return Finding{MatchTypes: []MatchType{ClassName}}, nil
+	} else if classHashMatch {
+		return Finding{MatchTypes: []MatchType{ClassHash}, HashEntry: &hashEntry}, nil
 	}
-	return []MatchType{}, nil
+	return Finding{}, nil
 }
@@ -0,0 +1,155 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxConfigSize limits how much of a configuration file or script is read.
+	maxConfigSize        = 4 << 20
+	log4jComponentConfig = "log4j2.component.properties"
+)
+
+var (
+	log4jConfigPattern    = regexp.MustCompile(`(?i)^log4j2[^/]*\.(xml|properties|ya?ml|json|jsn)$`)
+	lookupPattern         = regexp.MustCompile(`(?i)\$\{(jndi|ctx):`)
+	noLookupsPattern      = regexp.MustCompile(`(?i)(log4j2?\.formatMsgNoLookups|LOG4J_FORMAT_MSG_NO_LOOKUPS)\s*[=:]\s*["']?(true|false)`)
+	systemdDropInPattern  = regexp.MustCompile(`\.service\.d/[^/]+\.conf$`)
+	shellScriptExtensions = map[string]struct{}{".sh": {}, ".bash": {}, ".ksh": {}}
+)
+
+type configKind int
+
+const (
+	notConfig configKind = iota
+	log4jConfig
+	componentConfig
+	launchConfig
+)
+
+// ConfigScanner inspects log4j configuration for message lookups enabled in layouts
+// (CVE-2021-45046), and component properties, systemd units and shell scripts, such as setenv.sh,
+// for the formatMsgNoLookups mitigation.
+type ConfigScanner struct {
+	BaseDetector
+}
+
+func NewConfigScanner() ConfigScanner {
+	return ConfigScanner{}
+}
+
+func (s ConfigScanner) IsConfigFile(name string) bool {
+	return getConfigKind(name) != notConfig
+}
+
+func (s ConfigScanner) Name() string {
+	return ConfigDetectorName
+}
+
+func (s ConfigScanner) Accepts(entry Entry) bool {
+	return s.IsConfigFile(entry.Filename())
+}
+
+func (s ConfigScanner) InspectFile(entry Entry) (Finding, error) {
+	content, err := entry.Content()
+	if err != nil {
+		return Finding{}, err
+	}
+	matchTypes, findings, mitigations, err := s.Scan(entry.Filename(), bytes.NewReader(content))
+	if err != nil {
+		return Finding{}, err
+	}
+	return Finding{MatchTypes: matchTypes, Findings: findings, Mitigations: mitigations}, nil
+}
+
+func getConfigKind(name string) configKind {
+	name = strings.ReplaceAll(name, "\\", "/")
+	base := path.Base(name)
+	if strings.EqualFold(base, log4jComponentConfig) {
+		return componentConfig
+	}
+	if log4jConfigPattern.MatchString(base) {
+		return log4jConfig
+	}
+	if _, ok := shellScriptExtensions[strings.ToLower(path.Ext(base))]; ok {
+		return launchConfig
+	}
+	if strings.EqualFold(base, "setenv.bat") || strings.HasSuffix(base, ".service") || systemdDropInPattern.MatchString(name) {
+		return launchConfig
+	}
+	return notConfig
+}
+
+// Scan returns the match types and a description of each lookup found in the file, and each
+// formatMsgNoLookups setting, whether true or false.
+func (s ConfigScanner) Scan(name string, r io.Reader) ([]MatchType, []string, []MitigationSetting, error) {
+	kind := getConfigKind(name)
+	if kind == notConfig {
+		return []MatchType{}, nil, nil, nil
+	}
+	var findings []string
+	var matchTypes []MatchType
+	var mitigations []MitigationSetting
+	lineNumber := 0
+	scn := bufio.NewScanner(io.LimitReader(r, maxConfigSize))
+	scn.Buffer(make([]byte, 64*1024), maxConfigSize)
+	for scn.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scn.Text())
+		if kind != log4jConfig && (strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") || strings.HasPrefix(strings.ToUpper(line), "REM ")) {
+			continue
+		}
+		if kind == log4jConfig {
+			for _, m := range lookupPattern.FindAllString(line, -1) {
+				findings = append(findings, fmt.Sprintf("%s lookup on line %d", m, lineNumber))
+			}
+		} else {
+			for _, m := range noLookupsPattern.FindAllStringSubmatch(line, -1) {
+				mitigations = append(mitigations, MitigationSetting{
+					Description: fmt.Sprintf("%s=%s on line %d", m[1], m[2], lineNumber),
+					Enabled:     strings.EqualFold(m[2], "true"),
+				})
+			}
+		}
+	}
+	if err := scn.Err(); err != nil {
+		return []MatchType{}, nil, nil, fmt.Errorf("failed to read %s: %v", name, err)
+	}
+	if kind == log4jConfig && len(findings) > 0 {
+		matchTypes = append(matchTypes, ConfigLookup)
+	}
+	if len(matchTypes) == 0 {
+		return []MatchType{}, nil, mitigations, nil
+	}
+	return matchTypes, findings, mitigations, nil
+}
+
+func containsMatchType(matchTypes []MatchType, matchType MatchType) bool {
+	for _, m := range matchTypes {
+		if m == matchType {
+			return true
+		}
+	}
+	return false
+}
@@ -34,6 +34,7 @@ type Console interface {
 	NotMatched(progress Progress, message string)
 	Error(progress Progress, message string)
 	Skipped(progress Progress, message string)
+	Filtered(progress Progress, message string)
 }
 
 type console struct {
@@ -71,6 +72,14 @@ func (c *console) Skipped(progress Progress, message string) {
 	}
 }
 
+func (c *console) Filtered(progress Progress, message string) {
+	if c.verbosity > 0 {
+		c.println(gchalk.Grey("~~~"), message)
+	} else {
+		c.print(progress, gchalk.Grey("~~~"), message)
+	}
+}
+
 func (c *console) print(progress Progress, symbol string, message string) {
 	now := time.Now()
 	if now.Sub(c.lastUpdate).Milliseconds() > minProgressUpdateMs {
@@ -17,7 +17,6 @@ package lib
 
 import (
 	"bufio"
-	"crypto/sha256"
 	"fmt"
 	"hash"
 	"io"
@@ -152,19 +151,40 @@ type ContentReader interface {
 	Files() FileIterable
 	Filename() string
 	Hash() (string, error)
+	Hashes() (Digests, error)
 	Close() error
 }
 
+// IndexedContentReader is a ContentReader whose entries can be listed, and opened by name, before
+// they are iterated.
+type IndexedContentReader interface {
+	ContentReader
+	Names() []string
+	Open(name string) (ContentFile, error)
+}
+
 type FileIterable interface {
 	Next() (interface{}, error)
 }
 
+// FileFailure is returned by Next in place of an entry which could not be opened, so that the
+// remaining entries of the archive can still be read.
+type FileFailure struct {
+	Name string
+	Err  error
+}
+
+func (f FileFailure) Error() string {
+	return fmt.Sprintf("%s: %v", f.Name, f.Err)
+}
+
 type ContentFileReader interface {
 	io.ReadCloser
 	Filename() string
 	Size() int64
 	Header() []byte
 	Hash() (string, error)
+	Hashes() (Digests, error)
 }
 
 type contentFileReader struct {
@@ -173,13 +193,13 @@ type contentFileReader struct {
 	header           []byte
 	bufferedReader   BufferedTeeReader
 	underlyingReader io.ReadCloser
-	hasher           hash.Hash
-	hash             *string
+	hashers          map[HashAlgorithm]hash.Hash
+	digests          Digests
 	eof              bool
 }
 
 func NewContentFileReader(filename string, size int64, reader MaybeBufferedReadCloser) (ContentFileReader, error) {
-	hasher := sha256.New()
+	hashers, hasher := newHashers()
 	var bufferedReader BufferedReader
 	if reader.IsBuffered() {
 		bufferedReader = reader.(BufferedReader)
@@ -197,8 +217,8 @@ func NewContentFileReader(filename string, size int64, reader MaybeBufferedReadC
 		header:           header,
 		bufferedReader:   bufferedTeeReader,
 		underlyingReader: reader,
-		hasher:           hasher,
-		hash:             nil,
+		hashers:          hashers,
+		digests:          nil,
 		eof:              false,
 	}, nil
 }
@@ -216,17 +236,24 @@ func (b *contentFileReader) Header() []byte {
 }
 
 func (b *contentFileReader) Hash() (string, error) {
-	if b.hash == nil {
+	digests, err := b.Hashes()
+	if err != nil {
+		return "", err
+	}
+	return digests[SHA256], nil
+}
+
+func (b *contentFileReader) Hashes() (Digests, error) {
+	if b.digests == nil {
 		if !b.eof {
 			_, err := b.bufferedReader.SeekToEnd()
 			if err != nil {
-				return "", err
+				return nil, err
 			}
 		}
-		shaSum := fmt.Sprintf("%x", b.hasher.Sum(nil))
-		b.hash = &shaSum
+		b.digests = sumHashers(b.hashers)
 	}
-	return *b.hash, nil
+	return b.digests, nil
 }
 
 func (b *contentFileReader) Read(p []byte) (n int, err error) {
@@ -0,0 +1,227 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ContentRule matches files, or archive entries, whose name matches one of the Paths globs and
+// whose content contains the Strings, Hex byte sequences or Regex patterns.  Any pattern matching
+// is sufficient unless All is set.
+type ContentRule struct {
+	Id          string   `yaml:"id"`
+	Description string   `yaml:"description,omitempty"`
+	Paths       []string `yaml:"paths"`
+	Strings     []string `yaml:"strings,omitempty"`
+	Hex         []string `yaml:"hex,omitempty"`
+	Regex       []string `yaml:"regex,omitempty"`
+	All         bool     `yaml:"all,omitempty"`
+	sequences   [][]byte
+	patterns    []*regexp.Regexp
+}
+
+type ContentRules struct {
+	Rules []ContentRule `yaml:"rules"`
+}
+
+// ContentRuleMatch is the rule which matched and the offset of the first pattern it matched.
+type ContentRuleMatch struct {
+	RuleId string
+	Offset int
+}
+
+func (m ContentRuleMatch) String() string {
+	return fmt.Sprintf("%s at offset %d", m.RuleId, m.Offset)
+}
+
+func LoadContentRules(file string) (ContentRules, error) {
+	rules := ContentRules{}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return rules, err
+	}
+	if err := yaml.Unmarshal(content, &rules); err != nil {
+		return rules, err
+	}
+	ids := map[string]struct{}{}
+	for i := range rules.Rules {
+		if err := rules.Rules[i].compile(); err != nil {
+			return rules, fmt.Errorf("invalid rule %d: %v", i+1, err)
+		}
+		if _, ok := ids[rules.Rules[i].Id]; ok {
+			return rules, fmt.Errorf("invalid rule %d: duplicate id %s", i+1, rules.Rules[i].Id)
+		}
+		ids[rules.Rules[i].Id] = struct{}{}
+	}
+	return rules, nil
+}
+
+func (r *ContentRule) compile() error {
+	if len(strings.TrimSpace(r.Id)) == 0 {
+		return fmt.Errorf("id is required")
+	}
+	if len(r.Paths) == 0 {
+		return fmt.Errorf("paths is required")
+	}
+	for _, p := range r.Paths {
+		if !doublestar.ValidatePattern(p) {
+			return fmt.Errorf("invalid path glob: %s", p)
+		}
+	}
+	for _, s := range r.Strings {
+		r.sequences = append(r.sequences, []byte(s))
+	}
+	for _, h := range r.Hex {
+		sequence, err := hex.DecodeString(strings.Join(strings.Fields(h), ""))
+		if err != nil {
+			return fmt.Errorf("invalid hex %s: %v", h, err)
+		}
+		r.sequences = append(r.sequences, sequence)
+	}
+	for _, e := range r.Regex {
+		pattern, err := regexp.Compile(e)
+		if err != nil {
+			return fmt.Errorf("invalid regex %s: %v", e, err)
+		}
+		r.patterns = append(r.patterns, pattern)
+	}
+	if len(r.sequences) == 0 && len(r.patterns) == 0 {
+		return fmt.Errorf("strings, hex or regex is required")
+	}
+	return nil
+}
+
+// IsApplicable reports whether the rule applies to the file or entry name, which is matched with
+// and without its directory.
+func (r ContentRule) IsApplicable(name string) bool {
+	name = strings.ReplaceAll(name, "\\", "/")
+	for _, p := range r.Paths {
+		if ok, _ := doublestar.Match(p, name); ok {
+			return true
+		}
+		if ok, _ := doublestar.Match(p, path.Base(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the offset of the first pattern matched, or -1 when the rule does not match.
+func (r ContentRule) Match(content []byte) int {
+	offset := -1
+	matched := 0
+	record := func(i int) {
+		if i >= 0 {
+			matched++
+			if offset < 0 || i < offset {
+				offset = i
+			}
+		}
+	}
+	for _, s := range r.sequences {
+		record(bytes.Index(content, s))
+	}
+	for _, p := range r.patterns {
+		if loc := p.FindIndex(content); loc != nil {
+			record(loc[0])
+		} else {
+			record(-1)
+		}
+	}
+	if r.All && matched < len(r.sequences)+len(r.patterns) {
+		return -1
+	}
+	return offset
+}
+
+type ContentRuleMatcher interface {
+	IsApplicable(name string) bool
+	Match(name string, content []byte) []ContentRuleMatch
+}
+
+type contentRuleMatcher struct {
+	rules []ContentRule
+}
+
+func NewContentRuleMatcher(rules ContentRules) ContentRuleMatcher {
+	return &contentRuleMatcher{rules: rules.Rules}
+}
+
+func (m *contentRuleMatcher) IsApplicable(name string) bool {
+	for _, r := range m.rules {
+		if r.IsApplicable(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *contentRuleMatcher) Match(name string, content []byte) []ContentRuleMatch {
+	var matches []ContentRuleMatch
+	for _, r := range m.rules {
+		if !r.IsApplicable(name) {
+			continue
+		}
+		if offset := r.Match(content); offset >= 0 {
+			matches = append(matches, ContentRuleMatch{RuleId: r.Id, Offset: offset})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].RuleId < matches[j].RuleId
+	})
+	return matches
+}
+
+type contentRulesDetector struct {
+	BaseDetector
+	matcher ContentRuleMatcher
+}
+
+// NewContentRulesDetector creates the detector applying content rules to the start of each file and
+// archive entry they select.
+func NewContentRulesDetector(matcher ContentRuleMatcher) Detector {
+	return contentRulesDetector{matcher: matcher}
+}
+
+func (d contentRulesDetector) Name() string {
+	return ContentRulesDetectorName
+}
+
+func (d contentRulesDetector) Accepts(entry Entry) bool {
+	return d.matcher != nil && d.matcher.IsApplicable(entry.Name())
+}
+
+func (d contentRulesDetector) InspectFile(entry Entry) (Finding, error) {
+	content, err := entry.Content()
+	if err != nil {
+		return Finding{}, err
+	}
+	matches := d.matcher.Match(entry.Name(), content)
+	if len(matches) == 0 {
+		return Finding{}, nil
+	}
+	return Finding{MatchTypes: []MatchType{ContentPattern}, Rules: matches}, nil
+}
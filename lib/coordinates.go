@@ -0,0 +1,238 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	artifactExtensions = map[string]struct{}{
+		"jar": {}, "war": {}, "ear": {}, "zip": {}, "aar": {}, "rar": {}, "sar": {}, "nar": {}, "hpi": {}, "jpi": {},
+	}
+	// knownClassifiers are matched against the end of the text following the version, longest first.
+	knownClassifiers = []string{
+		"jar-with-dependencies", "test-sources", "test-javadoc", "with-dependencies", "sources", "javadoc",
+		"tests", "shaded", "uber", "all", "bin", "native", "standalone", "nodeps",
+	}
+	platformClassifier = regexp.MustCompile(`^(jdk|java|jre)[0-9]+$|^(linux|osx|macos|windows)$`)
+	// nonCodeClassifiers are attached artifacts which do not contain the bytecode of the artifact.
+	nonCodeClassifiers = map[string]struct{}{"sources": {}, "javadoc": {}, "test-sources": {}, "test-javadoc": {}}
+)
+
+// Coordinates identify a maven artifact.
+type Coordinates struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	Classifier string
+}
+
+// Label returns groupId:artifactId, or just the artifactId when the group is unknown.
+func (c Coordinates) Label() string {
+	if len(c.GroupId) == 0 {
+		return c.ArtifactId
+	}
+	return fmt.Sprintf("%s:%s", c.GroupId, c.ArtifactId)
+}
+
+func (c Coordinates) String() string {
+	if len(c.Version) == 0 {
+		return c.Label()
+	}
+	if len(c.Classifier) > 0 {
+		return fmt.Sprintf("%s:%s:%s", c.Label(), c.Version, c.Classifier)
+	}
+	return fmt.Sprintf("%s:%s", c.Label(), c.Version)
+}
+
+// IsCode reports whether the artifact contains bytecode, rather than being a sources or javadoc
+// artifact attached to it.
+func (c Coordinates) IsCode() bool {
+	_, ok := nonCodeClassifiers[c.Classifier]
+	return !ok
+}
+
+// IsPomProperties reports whether name is the META-INF/maven/<group>/<artifact>/pom.properties
+// entry maven adds to the jars it builds.
+func IsPomProperties(name string) bool {
+	parts := strings.Split(name, "/")
+	return len(parts) == 5 && parts[0] == "META-INF" && parts[1] == "maven" && parts[4] == "pom.properties"
+}
+
+func ParsePomProperties(r io.Reader) (Coordinates, error) {
+	coordinates := Coordinates{}
+	scn := bufio.NewScanner(r)
+	for scn.Scan() {
+		line := strings.TrimSpace(scn.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+		switch strings.TrimSpace(parts[0]) {
+		case "groupId":
+			coordinates.GroupId = value
+		case "artifactId":
+			coordinates.ArtifactId = value
+		case "version":
+			coordinates.Version = value
+		}
+	}
+	if err := scn.Err(); err != nil {
+		return coordinates, err
+	}
+	if len(coordinates.ArtifactId) == 0 {
+		return coordinates, fmt.Errorf("missing artifactId")
+	}
+	return coordinates, nil
+}
+
+// IsManifest reports whether name is the manifest of a jar.
+func IsManifest(name string) bool {
+	return strings.EqualFold(name, "META-INF/MANIFEST.MF")
+}
+
+// ParseManifest returns the version, the vendor id as the groupId, and the title as the artifactId
+// when it is a single word, declared by a jar manifest.
+func ParseManifest(r io.Reader) (Coordinates, error) {
+	attributes, err := parseManifestAttributes(r)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	coordinates := Coordinates{
+		GroupId: attributes["Implementation-Vendor-Id"],
+		Version: attributes["Implementation-Version"],
+	}
+	if len(coordinates.Version) == 0 {
+		coordinates.Version = attributes["Bundle-Version"]
+	}
+	if title := attributes["Implementation-Title"]; !strings.ContainsAny(title, " \t") {
+		coordinates.ArtifactId = title
+	}
+	return coordinates, nil
+}
+
+// parseManifestAttributes returns the main attributes of a jar manifest, joining continuation lines.
+func parseManifestAttributes(r io.Reader) (map[string]string, error) {
+	attributes := map[string]string{}
+	var last string
+	scn := bufio.NewScanner(r)
+	for scn.Scan() {
+		line := strings.TrimRight(scn.Text(), "\r")
+		if strings.HasPrefix(line, " ") && len(last) > 0 {
+			attributes[last] += line[1:]
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			last = ""
+			continue
+		}
+		last = strings.TrimSpace(parts[0])
+		attributes[last] = strings.TrimSpace(parts[1])
+	}
+	if err := scn.Err(); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// CoordinatesFromMavenLayout derives coordinates from a slash separated path relative to the root
+// of a maven repository, ie: org/apache/logging/log4j/log4j-core/2.14.1/log4j-core-2.14.1.jar
+func CoordinatesFromMavenLayout(relPath string) (Coordinates, bool) {
+	parts := strings.Split(path.Clean(relPath), "/")
+	if len(parts) < 4 {
+		return Coordinates{}, false
+	}
+	coordinates := Coordinates{
+		GroupId:    strings.Join(parts[:len(parts)-3], "."),
+		ArtifactId: parts[len(parts)-3],
+		Version:    parts[len(parts)-2],
+	}
+	classifier, ok := classifierFromFilename(parts[len(parts)-1], coordinates)
+	if !ok {
+		return Coordinates{}, false
+	}
+	coordinates.Classifier = classifier
+	return coordinates, true
+}
+
+// CoordinatesFromFilename parses a file name following the maven artifactId-version-classifier.ext
+// convention.  The artifactId ends at the last dash followed by a version, so log4j-core-java9-2.x.jar
+// is version 2.x of log4j-core-java9 and log4j-1.2-api-2.14.1.jar is version 2.14.1 of log4j-1.2-api.  Qualifiers and vendor suffixes, such as 2.0-beta9 and
+// 2.14.1.redhat-00001, remain part of the version while known classifiers, such as sources or
+// jdk8, are split from it.
+func CoordinatesFromFilename(filename string) Coordinates {
+	name := path.Base(filename)
+	if pos := strings.LastIndexByte(name, '.'); pos != -1 {
+		if _, ok := artifactExtensions[strings.ToLower(name[pos+1:])]; ok {
+			name = name[:pos]
+		}
+	}
+	coordinates := splitNameVersion(name)
+	coordinates.Version, coordinates.Classifier = splitVersionClassifier(coordinates.Version)
+	return coordinates
+}
+
+func splitVersionClassifier(version string) (string, string) {
+	lower := strings.ToLower(version)
+	for _, c := range knownClassifiers {
+		if strings.HasSuffix(lower, "-"+c) {
+			return version[:len(version)-len(c)-1], version[len(version)-len(c):]
+		}
+	}
+	parts := strings.Split(version, "-")
+	for i := 1; i < len(parts); i++ {
+		if platformClassifier.MatchString(strings.ToLower(parts[i])) {
+			return strings.Join(parts[:i], "-"), strings.Join(parts[i:], "-")
+		}
+	}
+	return version, ""
+}
+
+// splitNameVersion splits the name at the last dash followed by a digit whose preceding artifactId
+// does not itself end with a version.  So log4j-1.2-api-2.14.1 is version 2.14.1 of log4j-1.2-api,
+// while the 00001 of log4j-core-2.14.1.redhat-00001 remains part of the version.
+func splitNameVersion(name string) Coordinates {
+	split := -1
+	for i := len(name) - 2; i > 0; i-- {
+		if name[i] != '-' || !startsWithDigit(name[i+1:]) {
+			continue
+		}
+		split = i
+		artifactId := name[:i]
+		if !startsWithDigit(artifactId[strings.LastIndexByte(artifactId, '-')+1:]) {
+			break
+		}
+	}
+	if split < 0 {
+		return Coordinates{ArtifactId: name}
+	}
+	return Coordinates{ArtifactId: name[:split], Version: name[split+1:]}
+}
+
+func startsWithDigit(s string) bool {
+	return len(s) > 0 && s[0] >= '0' && s[0] <= '9'
+}
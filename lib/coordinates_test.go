@@ -0,0 +1,96 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"testing"
+)
+
+func TestCoordinatesFromFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected Coordinates
+	}{
+		{"log4j-core-2.14.1.jar", Coordinates{ArtifactId: "log4j-core", Version: "2.14.1"}},
+		{"log4j-1.2-api-2.14.1.jar", Coordinates{ArtifactId: "log4j-1.2-api", Version: "2.14.1"}},
+		{"log4j-core-2.14.1-sources.jar", Coordinates{ArtifactId: "log4j-core", Version: "2.14.1", Classifier: "sources"}},
+		{"log4j-core-2.14.1.redhat-00001.jar", Coordinates{ArtifactId: "log4j-core", Version: "2.14.1.redhat-00001"}},
+		{"log4j-core-java9-2.x.jar", Coordinates{ArtifactId: "log4j-core-java9", Version: "2.x"}},
+		{"log4j-core-2.0-beta9.jar", Coordinates{ArtifactId: "log4j-core", Version: "2.0-beta9"}},
+		{"log4j-1.2.17.jar", Coordinates{ArtifactId: "log4j", Version: "1.2.17"}},
+		{"commons-lang3-3.12.0-jdk8.jar", Coordinates{ArtifactId: "commons-lang3", Version: "3.12.0", Classifier: "jdk8"}},
+		{"spring-beans-5.3.17-jar-with-dependencies.jar", Coordinates{ArtifactId: "spring-beans", Version: "5.3.17", Classifier: "jar-with-dependencies"}},
+		{"app-1.0-20210101.123456-1.war", Coordinates{ArtifactId: "app", Version: "1.0-20210101.123456-1"}},
+		{"commons-text.jar", Coordinates{ArtifactId: "commons-text"}},
+		{"/opt/lib/log4j-api-2.17.1.jar", Coordinates{ArtifactId: "log4j-api", Version: "2.17.1"}},
+	}
+	for _, test := range tests {
+		if actual := CoordinatesFromFilename(test.filename); actual != test.expected {
+			t.Errorf("%s: expected %+v, got %+v", test.filename, test.expected, actual)
+		}
+	}
+}
+
+func TestJarNameMatcherDoesNotMatchBridgeAsLog4j1(t *testing.T) {
+	matcher := NewJarNameMatcher()
+	if err := matcher.AddMatchers("log4j/1.0/1.2.17"); err != nil {
+		t.Fatal(err)
+	}
+	if matcher.IsCoordinatesMatch(CoordinatesFromFilename("log4j-1.2-api-2.14.1.jar")) {
+		t.Errorf("expected log4j-1.2-api-2.14.1.jar not to match log4j 1.x")
+	}
+	if !matcher.IsCoordinatesMatch(CoordinatesFromFilename("log4j-1.2.17.jar")) {
+		t.Errorf("expected log4j-1.2.17.jar to match log4j 1.x")
+	}
+}
+
+func TestMavenVersionOrdering(t *testing.T) {
+	ordered := [][]string{
+		{"1.0-alpha1", "1.0-beta", "1.0-rc", "1.0", "1.0-sp"},
+		{"2.0-alpha1", "2.0-beta9", "2.0-rc1", "2.0-SNAPSHOT", "2.0", "2.0-sp1", "2.0.redhat-1", "2.0.1"},
+		{"2.14.1", "2.14.1.redhat-00001", "2.14.1.redhat-00002", "2.15.0"},
+		{"1.2.9", "1.2.10", "1.2.17", "1.2.18.3"},
+	}
+	for _, versions := range ordered {
+		for i := 0; i < len(versions)-1; i++ {
+			for j := i + 1; j < len(versions); j++ {
+				older := ParseMavenVersion(versions[i])
+				newer := ParseMavenVersion(versions[j])
+				if c := older.Compare(newer); c != -1 {
+					t.Errorf("expected %s < %s, got %d", older, newer, c)
+				}
+				if c := newer.Compare(older); c != 1 {
+					t.Errorf("expected %s > %s, got %d", newer, older, c)
+				}
+			}
+		}
+	}
+}
+
+func TestMavenVersionEquivalence(t *testing.T) {
+	equivalent := [][]string{
+		{"2.0", "2.0.0", "2.0-ga", "2.0-final", "2.0-release"},
+		{"1.0-cr1", "1.0-rc1"},
+		{"1.0-a1", "1.0-alpha1"},
+	}
+	for _, versions := range equivalent {
+		for _, v := range versions[1:] {
+			if c := ParseMavenVersion(versions[0]).Compare(ParseMavenVersion(v)); c != 0 {
+				t.Errorf("expected %s = %s, got %d", versions[0], v, c)
+			}
+		}
+	}
+}
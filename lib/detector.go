@@ -0,0 +1,178 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Finding is what a Detector reports about a file, archive entry or archive.
+type Finding struct {
+	MatchTypes []MatchType
+	HashEntry  *HashDatabaseEntry
+	Advisories []Advisory
+	Findings   []string
+	Rules      []ContentRuleMatch
+	// Components are inventoried whether or not the finding matched.
+	Components []Component
+	// Mitigations are reported separately from matches, whether or not the finding matched.
+	Mitigations []MitigationSetting
+	// Repository locates an archive within a maven repository or gradle cache, it is reported only
+	// when the archive has matches.
+	Repository *Repository
+}
+
+func (f Finding) IsMatch() bool {
+	return len(f.MatchTypes) > 0
+}
+
+// Detector inspects the files and archives found while scanning.  Detectors are enabled by name,
+// see RegisterDetector.
+type Detector interface {
+	Name() string
+	// Accepts reports whether InspectFile should be called for the file or archive entry.
+	Accepts(entry Entry) bool
+	// InspectFile inspects a file or archive entry, which may itself be an archive.
+	InspectFile(entry Entry) (Finding, error)
+	// InspectArchive inspects an archive before its entries are scanned.
+	InspectArchive(archive ContentReader) (Finding, error)
+	// InspectArchiveEntry inspects each entry of an archive, findings are reported against the archive.
+	InspectArchiveEntry(archive ContentReader, entry Entry) (Finding, error)
+}
+
+// BaseDetector implements every Detector hook as a no-op so detectors need only implement the hooks
+// they use.
+type BaseDetector struct {
+}
+
+func (BaseDetector) Accepts(Entry) bool {
+	return false
+}
+
+func (BaseDetector) InspectFile(Entry) (Finding, error) {
+	return Finding{}, nil
+}
+
+func (BaseDetector) InspectArchive(ContentReader) (Finding, error) {
+	return Finding{}, nil
+}
+
+func (BaseDetector) InspectArchiveEntry(ContentReader, Entry) (Finding, error) {
+	return Finding{}, nil
+}
+
+// DetectorOptions are the matchers and settings detectors are created with.
+type DetectorOptions struct {
+	ClassNameMatcher   ClassNameMatcher
+	ClassHashMatcher   HashMatcher
+	JarNameMatcher     JarNameMatcher
+	JarHashMatcher     HashMatcher
+	AdvisoryMatcher    AdvisoryMatcher
+	Repositories       bool
+	ContentRuleMatcher ContentRuleMatcher
+	// SpringFixedClassHashMatcher matches CachedIntrospectionResults classes of spring-beans versions
+	// fixed for Spring4Shell.
+	SpringFixedClassHashMatcher HashMatcher
+	// TextLookupClassHashMatcher matches StringLookupFactory and ScriptStringLookup classes of
+	// commons-text versions affected by Text4Shell.
+	TextLookupClassHashMatcher HashMatcher
+}
+
+type DetectorFactory func(options DetectorOptions) (Detector, error)
+
+var (
+	detectorFactories = map[string]DetectorFactory{}
+	// DefaultDetectors are the detectors enabled unless others are chosen.
+	DefaultDetectors = []string{ClassDetectorName, JarDetectorName, ConfigDetectorName, ContentRulesDetectorName}
+)
+
+const (
+	ClassDetectorName        = "class"
+	JarDetectorName          = "jar"
+	ConfigDetectorName       = "config"
+	ContentRulesDetectorName = "content-rules"
+	Spring4ShellDetectorName = "spring4shell"
+	Text4ShellDetectorName   = "text4shell"
+	LoggingDetectorName      = "logging"
+	JavaRuntimeDetectorName  = "java-runtime"
+)
+
+func init() {
+	RegisterDetector(ClassDetectorName, func(options DetectorOptions) (Detector, error) {
+		return NewClassScanner(options.ClassNameMatcher, options.ClassHashMatcher), nil
+	})
+	RegisterDetector(JarDetectorName, func(options DetectorOptions) (Detector, error) {
+		return NewJarScanner(options.JarNameMatcher, options.JarHashMatcher, options.AdvisoryMatcher, options.Repositories), nil
+	})
+	RegisterDetector(ConfigDetectorName, func(options DetectorOptions) (Detector, error) {
+		return NewConfigScanner(), nil
+	})
+	RegisterDetector(ContentRulesDetectorName, func(options DetectorOptions) (Detector, error) {
+		return NewContentRulesDetector(options.ContentRuleMatcher), nil
+	})
+	RegisterDetector(Spring4ShellDetectorName, func(options DetectorOptions) (Detector, error) {
+		return NewSpring4ShellDetector(options.SpringFixedClassHashMatcher)
+	})
+	RegisterDetector(Text4ShellDetectorName, func(options DetectorOptions) (Detector, error) {
+		return NewText4ShellDetector(options.TextLookupClassHashMatcher)
+	})
+	RegisterDetector(LoggingDetectorName, func(options DetectorOptions) (Detector, error) {
+		return NewLoggingDetector()
+	})
+	RegisterDetector(JavaRuntimeDetectorName, func(options DetectorOptions) (Detector, error) {
+		return NewJavaRuntimeDetector(), nil
+	})
+}
+
+// RegisterDetector makes a detector available to NewDetectors by name.
+func RegisterDetector(name string, factory DetectorFactory) {
+	detectorFactories[name] = factory
+}
+
+// DetectorNames returns the names of the registered detectors, sorted.
+func DetectorNames() []string {
+	names := make([]string, 0, len(detectorFactories))
+	for name := range detectorFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewDetectors creates the named detectors, in order.
+func NewDetectors(names []string, options DetectorOptions) ([]Detector, error) {
+	var detectors []Detector
+	seen := map[string]struct{}{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		factory, ok := detectorFactories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown detector %s, expected one of %s", name, strings.Join(DetectorNames(), ","))
+		}
+		detector, err := factory(options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create detector %s: %v", name, err)
+		}
+		detectors = append(detectors, detector)
+	}
+	return detectors, nil
+}
@@ -0,0 +1,157 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"sort"
+)
+
+type ChangedMatch struct {
+	Before ReportMatch `json:"before"`
+	After  ReportMatch `json:"after"`
+}
+
+// AddedMatchTypes returns the match types present after but not before.
+func (c ChangedMatch) AddedMatchTypes() []string {
+	return difference(c.After.MatchTypes, c.Before.MatchTypes)
+}
+
+func (c ChangedMatch) RemovedMatchTypes() []string {
+	return difference(c.Before.MatchTypes, c.After.MatchTypes)
+}
+
+// HashChanged reports whether the content of the matched file was replaced between the reports.
+func (c ChangedMatch) HashChanged() bool {
+	return c.Before.Hash != c.After.Hash
+}
+
+type ChangedFailure struct {
+	Before ReportFailure `json:"before"`
+	After  ReportFailure `json:"after"`
+}
+
+// AddedMessages returns the failure messages present after but not before.
+func (c ChangedFailure) AddedMessages() []string {
+	return difference(c.After.Messages, c.Before.Messages)
+}
+
+func (c ChangedFailure) RemovedMessages() []string {
+	return difference(c.Before.Messages, c.After.Messages)
+}
+
+type ReportDiff struct {
+	NewMatches       []ReportMatch    `json:"newMatches"`
+	ResolvedMatches  []ReportMatch    `json:"resolvedMatches"`
+	ChangedMatches   []ChangedMatch   `json:"changedMatches"`
+	NewFailures      []ReportFailure  `json:"newFailures"`
+	ResolvedFailures []ReportFailure  `json:"resolvedFailures"`
+	ChangedFailures  []ChangedFailure `json:"changedFailures"`
+}
+
+func DiffReports(before Report, after Report) ReportDiff {
+	diff := ReportDiff{
+		NewMatches:       []ReportMatch{},
+		ResolvedMatches:  []ReportMatch{},
+		ChangedMatches:   []ChangedMatch{},
+		NewFailures:      []ReportFailure{},
+		ResolvedFailures: []ReportFailure{},
+		ChangedFailures:  []ChangedFailure{},
+	}
+
+	beforeMatches := map[string]ReportMatch{}
+	for _, m := range before.Matches {
+		beforeMatches[m.Key()] = m
+	}
+	afterMatches := map[string]ReportMatch{}
+	for _, m := range after.Matches {
+		afterMatches[m.Key()] = m
+		if b, ok := beforeMatches[m.Key()]; !ok {
+			diff.NewMatches = append(diff.NewMatches, m)
+		} else {
+			c := ChangedMatch{Before: b, After: m}
+			if len(c.AddedMatchTypes()) > 0 || len(c.RemovedMatchTypes()) > 0 || c.HashChanged() {
+				diff.ChangedMatches = append(diff.ChangedMatches, c)
+			}
+		}
+	}
+	for _, m := range before.Matches {
+		if _, ok := afterMatches[m.Key()]; !ok {
+			diff.ResolvedMatches = append(diff.ResolvedMatches, m)
+		}
+	}
+
+	beforeFailures := map[string]ReportFailure{}
+	for _, f := range before.Failures {
+		beforeFailures[f.Id] = f
+	}
+	afterFailures := map[string]struct{}{}
+	for _, f := range after.Failures {
+		afterFailures[f.Id] = struct{}{}
+		if b, ok := beforeFailures[f.Id]; !ok {
+			diff.NewFailures = append(diff.NewFailures, f)
+		} else {
+			c := ChangedFailure{Before: b, After: f}
+			if len(c.AddedMessages()) > 0 || len(c.RemovedMessages()) > 0 {
+				diff.ChangedFailures = append(diff.ChangedFailures, c)
+			}
+		}
+	}
+	for _, f := range before.Failures {
+		if _, ok := afterFailures[f.Id]; !ok {
+			diff.ResolvedFailures = append(diff.ResolvedFailures, f)
+		}
+	}
+	return diff
+}
+
+// ExitCode returns ExitCodeMatches when there are new matches or matches with additional match
+// types and ExitCodeFailures when there are new failures or failures with additional messages.  A
+// replaced file whose match types are unchanged is not a regression.
+func (d ReportDiff) ExitCode() int {
+	exitCode := ExitCodeOk
+	if len(d.NewMatches) > 0 {
+		exitCode |= ExitCodeMatches
+	}
+	for _, c := range d.ChangedMatches {
+		if len(c.AddedMatchTypes()) > 0 {
+			exitCode |= ExitCodeMatches
+		}
+	}
+	if len(d.NewFailures) > 0 {
+		exitCode |= ExitCodeFailures
+	}
+	for _, c := range d.ChangedFailures {
+		if len(c.AddedMessages()) > 0 {
+			exitCode |= ExitCodeFailures
+		}
+	}
+	return exitCode
+}
+
+func difference(a []string, b []string) []string {
+	bs := map[string]struct{}{}
+	for _, v := range b {
+		bs[v] = struct{}{}
+	}
+	result := []string{}
+	for _, v := range a {
+		if _, ok := bs[v]; !ok {
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
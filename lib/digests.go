@@ -0,0 +1,139 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+type HashAlgorithm string
+
+const (
+	MD5    HashAlgorithm = "md5"
+	SHA1   HashAlgorithm = "sha1"
+	SHA256 HashAlgorithm = "sha256"
+	SHA512 HashAlgorithm = "sha512"
+)
+
+// HashAlgorithms are the algorithms which hashes may be declared with.
+var HashAlgorithms = []HashAlgorithm{MD5, SHA1, SHA256, SHA512}
+
+// computedHashAlgorithms are the algorithms computed for the content of every file read, being
+// SHA256, which reports show, and those required by the hashes and suppressions loaded.
+var computedHashAlgorithms = []HashAlgorithm{SHA256}
+
+// RequireHashAlgorithm computes the algorithm for the content of every file read from then on.  It
+// is called as hashes are loaded, before scanning, and is not safe for concurrent use.
+func RequireHashAlgorithm(algorithm HashAlgorithm) {
+	for _, a := range computedHashAlgorithms {
+		if a == algorithm {
+			return
+		}
+	}
+	computedHashAlgorithms = append(computedHashAlgorithms, algorithm)
+}
+
+// Digests are the hex encoded digests of content keyed by algorithm.
+type Digests map[HashAlgorithm]string
+
+func newHasher(algorithm HashAlgorithm) hash.Hash {
+	switch algorithm {
+	case MD5:
+		return md5.New()
+	case SHA1:
+		return sha1.New()
+	case SHA512:
+		return sha512.New()
+	}
+	return sha256.New()
+}
+
+// newHashers creates a hasher for each of the computed algorithms and a writer to all of them.
+func newHashers() (map[HashAlgorithm]hash.Hash, io.Writer) {
+	hashers := make(map[HashAlgorithm]hash.Hash, len(computedHashAlgorithms))
+	writers := make([]io.Writer, len(computedHashAlgorithms))
+	for i, algorithm := range computedHashAlgorithms {
+		hashers[algorithm] = newHasher(algorithm)
+		writers[i] = hashers[algorithm]
+	}
+	return hashers, io.MultiWriter(writers...)
+}
+
+func sumHashers(hashers map[HashAlgorithm]hash.Hash) Digests {
+	digests := make(Digests, len(hashers))
+	for algorithm, hasher := range hashers {
+		digests[algorithm] = fmt.Sprintf("%x", hasher.Sum(nil))
+	}
+	return digests
+}
+
+// ComputeDigests reads the content to the end and returns its digests.
+func ComputeDigests(r io.Reader) (Digests, error) {
+	hashers, writer := newHashers()
+	if _, err := io.Copy(writer, r); err != nil {
+		return nil, err
+	}
+	return sumHashers(hashers), nil
+}
+
+// ParseHash parses a hex digest optionally prefixed with its algorithm, ie: sha1:<hex>.  Without
+// a prefix the algorithm is detected from the length of the digest.
+func ParseHash(value string) (HashAlgorithm, string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if parts := strings.SplitN(value, ":", 2); len(parts) == 2 {
+		algorithm := HashAlgorithm(strings.ReplaceAll(parts[0], "-", ""))
+		for _, a := range HashAlgorithms {
+			if a == algorithm {
+				return algorithm, parts[1], validateHash(algorithm, parts[1])
+			}
+		}
+		return "", "", fmt.Errorf("unknown hash algorithm: %s", parts[0])
+	}
+	var algorithm HashAlgorithm
+	switch len(value) {
+	case md5.Size * 2:
+		algorithm = MD5
+	case sha1.Size * 2:
+		algorithm = SHA1
+	case sha256.Size * 2:
+		algorithm = SHA256
+	case sha512.Size * 2:
+		algorithm = SHA512
+	default:
+		return "", "", fmt.Errorf("unable to detect algorithm of hash: %s", value)
+	}
+	return algorithm, value, validateHash(algorithm, value)
+}
+
+// validateHash checks that the lower case hash is hex of the length of the algorithm's digests.
+func validateHash(algorithm HashAlgorithm, hash string) error {
+	if size := newHasher(algorithm).Size() * 2; len(hash) != size {
+		return fmt.Errorf("invalid %s hash, expected %d hex digits: %s", algorithm, size, hash)
+	}
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return fmt.Errorf("invalid %s hash, expected hex digits: %s", algorithm, hash)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHash(t *testing.T) {
+	sha1 := strings.Repeat("a", 40)
+	sha256 := strings.Repeat("b", 64)
+	tests := []struct {
+		value     string
+		algorithm HashAlgorithm
+		hash      string
+		valid     bool
+	}{
+		{sha256, SHA256, sha256, true},
+		{strings.ToUpper(sha1), SHA1, sha1, true},
+		{"sha1:" + sha1, SHA1, sha1, true},
+		{"SHA-256:" + sha256, SHA256, sha256, true},
+		{"sha1:abc", "", "", false},
+		{"sha256:" + sha1, "", "", false},
+		{"md5:" + strings.Repeat("g", 32), "", "", false},
+		{strings.Repeat("z", 64), "", "", false},
+		{"crc32:" + sha1, "", "", false},
+		{"abc123", "", "", false},
+	}
+	for _, test := range tests {
+		algorithm, hash, err := ParseHash(test.value)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.value, err)
+		} else if algorithm != test.algorithm || hash != test.hash {
+			t.Errorf("%s: expected %s:%s, got %s:%s", test.value, test.algorithm, test.hash, algorithm, hash)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// maxEntryContentSize limits how much of a file or archive entry is read for detectors.
+const maxEntryContentSize = 64 << 20
+
+// Entry is a file, or archive entry, being scanned.  Its content is read at most once so that any
+// number of detectors can inspect it before it is opened as an archive.
+type Entry interface {
+	// Name is the archive entry name, or the root relative path of a file.
+	Name() string
+	// Filename is the archive entry name, or the path of a file.
+	Filename() string
+	// Archive is the filename of the archive containing the entry, or empty for a file.
+	Archive() string
+	// Content returns the first maxEntryContentSize bytes of content.
+	Content() ([]byte, error)
+	Hashes() (Digests, error)
+}
+
+type scanEntry interface {
+	Entry
+	open(globMatcher GlobMatcher) (ContentReader, error)
+}
+
+type fileEntry struct {
+	name     string
+	filename string
+	content  []byte
+	complete bool
+	digests  Digests
+}
+
+func newFileEntry(name string, filename string) *fileEntry {
+	return &fileEntry{name: name, filename: filename}
+}
+
+func (e *fileEntry) Name() string {
+	return e.name
+}
+
+func (e *fileEntry) Filename() string {
+	return e.filename
+}
+
+func (e *fileEntry) Archive() string {
+	return ""
+}
+
+func (e *fileEntry) Content() ([]byte, error) {
+	if e.content == nil {
+		f, err := os.Open(e.filename)
+		if err != nil {
+			return nil, err
+		}
+		defer func(file *os.File) {
+			_ = file.Close()
+		}(f)
+		content, _, complete, err := readEntryContent(f)
+		if err != nil {
+			return nil, err
+		}
+		e.content = content
+		e.complete = complete
+	}
+	return e.content, nil
+}
+
+func (e *fileEntry) Hashes() (Digests, error) {
+	if e.digests == nil {
+		if e.content != nil && e.complete {
+			digests, err := ComputeDigests(bytes.NewReader(e.content))
+			if err != nil {
+				return nil, err
+			}
+			e.digests = digests
+			return e.digests, nil
+		}
+		f, err := os.Open(e.filename)
+		if err != nil {
+			return nil, err
+		}
+		defer func(file *os.File) {
+			_ = file.Close()
+		}(f)
+		digests, err := ComputeDigests(f)
+		if err != nil {
+			return nil, err
+		}
+		e.digests = digests
+	}
+	return e.digests, nil
+}
+
+func (e *fileEntry) open(globMatcher GlobMatcher) (ContentReader, error) {
+	return GetContentReaderFromFile(e.filename, globMatcher)
+}
+
+// contentFileEntry is an archive entry whose content, once read by a detector, is replayed when
+// it is opened as an archive.
+type contentFileEntry struct {
+	contentFile ContentFile
+	archive     string
+	reader      ContentFileReader
+	content     []byte
+	complete    bool
+	spool       *os.File
+	digests     Digests
+}
+
+func newContentFileEntry(archive string, contentFile ContentFile) *contentFileEntry {
+	return &contentFileEntry{contentFile: contentFile, archive: archive, reader: contentFile.Reader()}
+}
+
+func (e *contentFileEntry) Name() string {
+	return e.contentFile.Name()
+}
+
+func (e *contentFileEntry) Filename() string {
+	return e.contentFile.Name()
+}
+
+func (e *contentFileEntry) Archive() string {
+	return e.archive
+}
+
+func (e *contentFileEntry) Content() ([]byte, error) {
+	if e.content == nil {
+		content, read, complete, err := readEntryContent(e.reader)
+		if err != nil {
+			return nil, err
+		}
+		reader, err := NewContentFileReader(e.reader.Filename(), e.reader.Size(),
+			NewNopUnbufferedCloser(io.MultiReader(bytes.NewReader(read), e.reader)))
+		if err != nil {
+			return nil, err
+		}
+		e.reader = reader
+		e.content = content
+		e.complete = complete
+	}
+	return e.content, nil
+}
+
+// Hashes returns the digests of the entry.  Entries larger than maxEntryContentSize are hashed while
+// spooling them to a temporary file, from which they can still be opened as an archive.
+func (e *contentFileEntry) Hashes() (Digests, error) {
+	if e.digests == nil {
+		content, err := e.Content()
+		if err != nil {
+			return nil, err
+		}
+		if e.complete {
+			e.digests, err = ComputeDigests(bytes.NewReader(content))
+		} else {
+			e.digests, err = e.spoolContent()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e.digests, nil
+}
+
+// spoolContent reads the rest of the content into a temporary file, returning its digests, and
+// replaces the reader of the entry with one of the temporary file.
+func (e *contentFileEntry) spoolContent() (Digests, error) {
+	spool, err := os.CreateTemp("", "log4shell-scanner-*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to spool content of %s: %v", e.Name(), err)
+	}
+	e.spool = spool
+	size, err := io.Copy(spool, e.reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to spool content of %s: %v", e.Name(), err)
+	}
+	digests, err := e.reader.Hashes()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	reader, err := NewContentFileReader(e.reader.Filename(), size, NewNopUnbufferedCloser(spool))
+	if err != nil {
+		return nil, err
+	}
+	e.reader = &randomAccessFileReader{ContentFileReader: reader, file: spool}
+	return digests, nil
+}
+
+func (e *contentFileEntry) open(globMatcher GlobMatcher) (ContentReader, error) {
+	return GetContentReader(e.reader, globMatcher)
+}
+
+func (e *contentFileEntry) Close() error {
+	err := e.contentFile.Close()
+	if e.spool != nil {
+		_ = e.spool.Close()
+		_ = os.Remove(e.spool.Name())
+	}
+	return err
+}
+
+// readEntryContent reads up to maxEntryContentSize bytes, reporting whether that was all of it.  read
+// holds every byte read, which is one more than the content when it is incomplete, for replaying.
+func readEntryContent(r io.Reader) (content []byte, read []byte, complete bool, err error) {
+	read, err = io.ReadAll(io.LimitReader(r, maxEntryContentSize+1))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if len(read) > maxEntryContentSize {
+		return read[:maxEntryContentSize], read, false, nil
+	}
+	return read, read, true, nil
+}
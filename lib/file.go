@@ -27,6 +27,8 @@ import (
 	"sync"
 )
 
+var jmodMagic = []byte{'J', 'M', 1, 0}
+
 var bufferPool = sync.Pool{
 	New: func() interface{} {
 		return new(bytes.Buffer)
@@ -46,13 +48,49 @@ func GetContentReaderFromFile(filename string, globMatcher GlobMatcher) (Content
 	if err != nil {
 		return nil, err
 	}
-	reader, err := GetContentReader(fileReader, globMatcher)
+	reader, err := GetContentReader(&randomAccessFileReader{ContentFileReader: fileReader, file: f}, globMatcher)
 	if err != nil || reader == nil {
 		_ = f.Close()
 	}
 	return reader, err
 }
 
+// randomAccessFileReader is a ContentFileReader of a file which supports random access, so that
+// archives need not be buffered in memory and the end of other files can be searched for an
+// appended zip.
+type randomAccessFileReader struct {
+	ContentFileReader
+	file *os.File
+}
+
+func (r *randomAccessFileReader) ReadAt(p []byte, off int64) (int, error) {
+	return r.file.ReadAt(p, off)
+}
+
+// IsGzip reports whether the content starts with the gzip magic number.
+func IsGzip(reader ContentFileReader) bool {
+	return isGzipHeader(reader.Header())
+}
+
+func isGzipHeader(header []byte) bool {
+	kind, _ := filetype.Match(header)
+	return kind.Extension == "gz"
+}
+
+// NewGzipContentFileReader returns a reader of the uncompressed content of a gzip compressed reader.
+func NewGzipContentFileReader(reader ContentFileReader) (ContentFileReader, error) {
+	uncompressedStream, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open gzip file: %v", err)
+	}
+	bufferedReader := NewBufferedReadCloser(uncompressedStream)
+	contentFileReader, err := NewContentFileReader(reader.Filename(), -1, bufferedReader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create content reader: %v", err)
+	}
+	return contentFileReader, nil
+}
+
 func GetContentReader(reader ContentFileReader, globMatcher GlobMatcher) (ContentReader, error) {
 	kind, _ := filetype.Match(reader.Header())
 	switch kind.Extension {
@@ -64,45 +102,116 @@ func GetContentReader(reader ContentFileReader, globMatcher GlobMatcher) (Conten
 		}
 		return NewTarReader(reader.Filename(), tarReader, reader, globMatcher), nil
 	case "gz":
-		uncompressedStream, err := gzip.NewReader(reader)
-		if err != nil {
-			return nil, fmt.Errorf("unable to open gzip file: %v", err)
-		}
-		bufferedReader := NewBufferedReadCloser(uncompressedStream)
-		contentFileReader, err := NewContentFileReader(reader.Filename(), -1, bufferedReader)
+		contentFileReader, err := NewGzipContentFileReader(reader)
 		if err != nil {
-			return nil, fmt.Errorf("unable to create content reader: %v", err)
+			return nil, err
 		}
 		return GetContentReader(contentFileReader, globMatcher)
 	case "zip":
-		var size int64
-		var randomAccessReader io.ReaderAt
-		var closer io.Closer
-		if _, ok := reader.(io.ReaderAt); !ok || reader.Size() < 0 {
-			buffer := bufferPool.Get().(*bytes.Buffer)
-			buffer.Reset()
-			closer = Closer(func() error {
-				bufferPool.Put(buffer)
-				return nil
-			})
-			_, err := buffer.ReadFrom(reader)
-			if err != nil {
-				_ = closer.Close()
-				return nil, fmt.Errorf("unable to buffer data zip stream: %v", err)
-			}
-			_ = reader.Close()
-			randomAccessReader = bytes.NewReader(buffer.Bytes())
-			size = int64(buffer.Len())
-		} else {
-			randomAccessReader = reader.(io.ReaderAt)
-			size = reader.Size()
+		randomAccessReader, size, closer, err := randomAccess(reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to buffer data zip stream: %v", err)
 		}
-		r, err := zip.NewReader(randomAccessReader, size)
+		r, err := openZip(reader, randomAccessReader, size, globMatcher, closer)
 		if err != nil {
 			_ = closer.Close()
 			return nil, fmt.Errorf("unable to open zip file: %v", err)
 		}
-		return NewZipReader(reader.Filename(), r, reader, globMatcher, closer), nil
+		return r, nil
+	}
+	if IsJmod(reader.Header()) {
+		randomAccessReader, size, closer, err := randomAccess(reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to buffer data jmod stream: %v", err)
+		}
+		zipSize := size - int64(len(jmodMagic))
+		r, err := openZip(reader, io.NewSectionReader(randomAccessReader, int64(len(jmodMagic)), zipSize), zipSize, globMatcher, closer)
+		if err != nil {
+			_ = closer.Close()
+			return nil, fmt.Errorf("unable to open jmod file: %v", err)
+		}
+		return r, nil
+	}
+	if IsJimage(reader.Header()) {
+		randomAccessReader, size, closer, err := randomAccess(reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to buffer data jimage stream: %v", err)
+		}
+		r, err := NewJimageReader(reader.Filename(), randomAccessReader, size, reader, globMatcher, closer)
+		if err != nil {
+			_ = closer.Close()
+			return nil, fmt.Errorf("unable to open jimage file: %v", err)
+		}
+		return r, nil
+	}
+	if randomAccessReader, ok := reader.(io.ReaderAt); ok && reader.Size() >= 0 {
+		return openEmbeddedZip(reader, randomAccessReader, reader.Size(), globMatcher, Closer(func() error { return nil })), nil
+	}
+	if isSelfExtractingName(reader.Filename()) {
+		randomAccessReader, size, closer, err := randomAccess(reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to buffer data self-extracting stream: %v", err)
+		}
+		r := openEmbeddedZip(reader, randomAccessReader, size, globMatcher, closer)
+		if r == nil {
+			_ = closer.Close()
+		}
+		return r, nil
 	}
 	return nil, nil
 }
+
+// openZip opens the zip content, recovering its entries from their local file headers when the
+// central directory cannot be read.
+func openZip(reader ContentFileReader, randomAccessReader io.ReaderAt, size int64, globMatcher GlobMatcher, closer io.Closer) (ContentReader, error) {
+	r, err := zip.NewReader(randomAccessReader, size)
+	if err == nil {
+		return NewZipReader(reader.Filename(), r, reader, globMatcher, closer), nil
+	}
+	recovered, recoveryErr := NewRecoveredZipReader(reader.Filename(), randomAccessReader, size, reader, globMatcher, closer)
+	if recoveryErr != nil {
+		return nil, fmt.Errorf("%v: %v", err, recoveryErr)
+	}
+	return recovered, nil
+}
+
+// openEmbeddedZip opens the zip appended to the content, such as that of a self-extracting
+// installer, returning nil when there is none or it cannot be read.
+func openEmbeddedZip(reader ContentFileReader, randomAccessReader io.ReaderAt, size int64, globMatcher GlobMatcher, closer io.Closer) ContentReader {
+	base, ok := findEmbeddedZip(randomAccessReader, size)
+	if !ok {
+		return nil
+	}
+	r, err := zip.NewReader(io.NewSectionReader(randomAccessReader, base, size-base), size-base)
+	if err != nil {
+		return nil
+	}
+	return NewZipReader(reader.Filename(), r, reader, globMatcher, closer)
+}
+
+// IsJmod reports whether the content starts with the header of a jmod, a zip prefixed by "JM" and
+// its version.
+func IsJmod(header []byte) bool {
+	return bytes.HasPrefix(header, jmodMagic)
+}
+
+// randomAccess returns random access to the content, buffering it in memory unless the reader
+// supports random access itself.
+func randomAccess(reader ContentFileReader) (io.ReaderAt, int64, io.Closer, error) {
+	if randomAccessReader, ok := reader.(io.ReaderAt); ok && reader.Size() >= 0 {
+		return randomAccessReader, reader.Size(), Closer(func() error { return nil }), nil
+	}
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	closer := Closer(func() error {
+		bufferPool.Put(buffer)
+		return nil
+	})
+	_, err := buffer.ReadFrom(reader)
+	if err != nil {
+		_ = closer.Close()
+		return nil, 0, nil, err
+	}
+	_ = reader.Close()
+	return bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), closer, nil
+}
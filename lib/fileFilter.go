@@ -0,0 +1,124 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"github.com/h2non/filetype"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var archiveExtensions = map[string]struct{}{
+	".jar":  {},
+	".war":  {},
+	".ear":  {},
+	".zip":  {},
+	".tar":  {},
+	".gz":   {},
+	".tgz":  {},
+	".jmod": {},
+}
+
+type FileFilter interface {
+	IsFiltered(path string, config bool) bool
+}
+
+type fileFilter struct {
+	minSize        int64
+	maxSize        int64
+	modifiedSince  time.Time
+	modifiedBefore time.Time
+	onlyArchives   bool
+}
+
+func NewFileFilter(minSize int64, maxSize int64, modifiedSince time.Time, modifiedBefore time.Time, onlyArchives bool) FileFilter {
+	return &fileFilter{
+		minSize:        minSize,
+		maxSize:        maxSize,
+		modifiedSince:  modifiedSince,
+		modifiedBefore: modifiedBefore,
+		onlyArchives:   onlyArchives,
+	}
+}
+
+// IsFiltered reports whether path should be excluded from the scan without opening it.  The file is
+// only stat'ed when a size or modification time filter is set, and only has its header read when
+// only archives are scanned.  Files which cannot be stat'ed are never filtered so that the failure is
+// reported when the scan opens them.  Configuration files, which the config detector accepts, are
+// scanned for mitigations and lookups even when only archives are scanned.
+func (f *fileFilter) IsFiltered(path string, config bool) bool {
+	if f.minSize > 0 || f.maxSize > 0 || !f.modifiedSince.IsZero() || !f.modifiedBefore.IsZero() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false
+		}
+		if f.minSize > 0 && info.Size() < f.minSize {
+			return true
+		}
+		if f.maxSize > 0 && info.Size() > f.maxSize {
+			return true
+		}
+		if !f.modifiedSince.IsZero() && info.ModTime().Before(f.modifiedSince) {
+			return true
+		}
+		if !f.modifiedBefore.IsZero() && !info.ModTime().Before(f.modifiedBefore) {
+			return true
+		}
+	}
+	if f.onlyArchives && !config && !isArchive(path) {
+		return true
+	}
+	return false
+}
+
+// isArchive reports whether the file is an archive by its extension, or the magic number in its
+// header.  Installers, which may have a zip appended, also have the end of the file searched for one.
+func isArchive(path string) bool {
+	if _, ok := archiveExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return true
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return true
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+	header := make([]byte, 262)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return true
+	}
+	if IsJmod(header[:n]) || IsJimage(header[:n]) {
+		return true
+	}
+	kind, _ := filetype.Match(header[:n])
+	switch kind.Extension {
+	case "tar", "gz", "zip":
+		return true
+	}
+	if isSelfExtractingName(path) {
+		if info, err := file.Stat(); err == nil {
+			if _, ok := findEmbeddedZip(file, info.Size()); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const hashDatabaseHeader = "# hash\tlabel\tversion\tcves\tsource"
+
+// HashDatabaseEntry is a line of an annotated hash file.  Annotated hash files are tab separated
+// and, because the hash is the first column, remain loadable as plain sha256sum style files.
+type HashDatabaseEntry struct {
+	Algorithm HashAlgorithm
+	Hash      string
+	Label     string
+	Version   string
+	CVEs      []string
+	Source    string
+}
+
+func (e HashDatabaseEntry) String() string {
+	hash := e.Hash
+	if len(e.Algorithm) > 0 && e.Algorithm != SHA256 {
+		hash = fmt.Sprintf("%s:%s", e.Algorithm, e.Hash)
+	}
+	return strings.Join([]string{hash, e.Label, e.Version, strings.Join(e.CVEs, ","), e.Source}, "\t")
+}
+
+// Description summarizes the entry, ie: org.apache.logging.log4j:log4j-core 2.14.1 CVE-2021-44228
+func (e HashDatabaseEntry) Description() string {
+	parts := []string{}
+	for _, p := range append([]string{e.Label, e.Version}, e.CVEs...) {
+		if len(p) > 0 {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return e.Source
+	}
+	return strings.Join(parts, " ")
+}
+
+func WriteHashDatabase(w io.Writer, entries []HashDatabaseEntry) error {
+	sorted := make([]HashDatabaseEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Label != sorted[j].Label {
+			return sorted[i].Label < sorted[j].Label
+		}
+		return sorted[i].Source < sorted[j].Source
+	})
+	if _, err := fmt.Fprintln(w, hashDatabaseHeader); err != nil {
+		return err
+	}
+	for _, e := range sorted {
+		if _, err := fmt.Fprintln(w, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type HashDatabaseBuilder interface {
+	Build(roots ...string) (jars []HashDatabaseEntry, classes []HashDatabaseEntry, err error)
+}
+
+type hashDatabaseBuilder struct {
+	classNameMatcher ClassNameMatcher
+	globMatcher      GlobMatcher
+	cves             []string
+	console          Console
+}
+
+func NewHashDatabaseBuilder(classNameMatcher ClassNameMatcher, globMatcher GlobMatcher, cves []string, verbosity int) HashDatabaseBuilder {
+	return &hashDatabaseBuilder{
+		classNameMatcher: classNameMatcher,
+		globMatcher:      globMatcher,
+		cves:             cves,
+		console:          NewConsole(verbosity),
+	}
+}
+
+// Build hashes every jar beneath roots, and the classes within them that match the class name
+// matcher, labelling each with the coordinates from the jar's pom.properties, its location in a
+// maven repository layout, or its file name.
+func (b *hashDatabaseBuilder) Build(roots ...string) ([]HashDatabaseEntry, []HashDatabaseEntry, error) {
+	var jars []HashDatabaseEntry
+	var classes []HashDatabaseEntry
+	walker, err := NewWalker(b.globMatcher, WalkOptions{MaxDepth: -1})
+	if err != nil {
+		return nil, nil, err
+	}
+	err = walker.WalkDirs(func(fileId string, filePath string, _ fs.FileMode, progress Progress) error {
+		if !strings.HasSuffix(filePath, ".jar") {
+			return nil
+		}
+		jar, jarClasses, err := b.hashJar(fileId, filePath)
+		if err != nil {
+			b.console.Error(progress, fmt.Sprintf("%s: %v", fileId, err))
+			return nil
+		}
+		b.console.Matched(progress, fmt.Sprintf("%s (%s %s)", fileId, jar.Label, jar.Version))
+		jars = append(jars, jar)
+		classes = append(classes, jarClasses...)
+		return nil
+	}, roots...)
+	return jars, classes, err
+}
+
+func (b *hashDatabaseBuilder) hashJar(fileId string, filePath string) (HashDatabaseEntry, []HashDatabaseEntry, error) {
+	reader, err := GetContentReaderFromFile(filePath, b.globMatcher)
+	if err != nil {
+		return HashDatabaseEntry{}, nil, err
+	}
+	if reader == nil {
+		return HashDatabaseEntry{}, nil, fmt.Errorf("not an archive")
+	}
+	defer func(reader ContentReader) {
+		_ = reader.Close()
+	}(reader)
+
+	hash, err := reader.Hash()
+	if err != nil {
+		return HashDatabaseEntry{}, nil, fmt.Errorf("failed to get hash: %v", err)
+	}
+	coordinates, ok := CoordinatesFromMavenLayout(filepath.ToSlash(fileId))
+	if !ok {
+		coordinates = CoordinatesFromFilename(filePath)
+	}
+
+	var classes []HashDatabaseEntry
+	var pomCoordinates []Coordinates
+	files := reader.Files()
+	for {
+		next, err := files.Next()
+		if err != nil {
+			return HashDatabaseEntry{}, nil, fmt.Errorf("failed to get next archive file: %v", err)
+		}
+		if next == nil {
+			break
+		}
+		contentFile, ok := next.(ContentFile)
+		if !ok {
+			continue
+		}
+		if IsPomProperties(contentFile.Name()) {
+			if c, err := ParsePomProperties(contentFile.Reader()); err == nil {
+				pomCoordinates = append(pomCoordinates, c)
+			}
+		} else if strings.HasSuffix(contentFile.Name(), ".class") {
+			match, err := b.classNameMatcher.IsMatch(filepath.Base(contentFile.Name()))
+			if err == nil && match {
+				classHash, err := contentFile.Reader().Hash()
+				if err != nil {
+					_ = contentFile.Close()
+					return HashDatabaseEntry{}, nil, fmt.Errorf("failed to hash %s: %v", contentFile.Name(), err)
+				}
+				classes = append(classes, HashDatabaseEntry{Hash: classHash, Source: JoinPath([]string{fileId, contentFile.Name()})})
+			}
+		}
+		_ = contentFile.Close()
+	}
+
+	coordinates = selectPomCoordinates(coordinates, filepath.Base(filePath), pomCoordinates)
+	for i := range classes {
+		classes[i].Label = coordinates.Label()
+		classes[i].Version = coordinates.Version
+		classes[i].CVEs = b.cves
+	}
+	return HashDatabaseEntry{
+		Hash:    hash,
+		Label:   coordinates.Label(),
+		Version: coordinates.Version,
+		CVEs:    b.cves,
+		Source:  fileId,
+	}, classes, nil
+}
+
+// selectPomCoordinates prefers the pom.properties describing the jar itself over those of any
+// dependencies shaded into it.
+func selectPomCoordinates(coordinates Coordinates, filename string, pomCoordinates []Coordinates) Coordinates {
+	if c, ok := findPomCoordinates(filename, pomCoordinates); ok {
+		return c
+	}
+	return coordinates
+}
+
+// findPomCoordinates returns the pom.properties coordinates describing the jar itself, being those
+// whose artifactId prefixes the file name, or the only ones present.
+func findPomCoordinates(filename string, pomCoordinates []Coordinates) (Coordinates, bool) {
+	for _, c := range pomCoordinates {
+		if strings.HasPrefix(filename, fmt.Sprintf("%s-", c.ArtifactId)) {
+			return c, true
+		}
+	}
+	if len(pomCoordinates) == 1 {
+		return pomCoordinates[0], true
+	}
+	return Coordinates{}, false
+}
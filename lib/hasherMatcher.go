@@ -17,22 +17,26 @@ package lib
 
 import (
 	"bufio"
-	"log"
+	"fmt"
 	"os"
 	"strings"
 )
 
+// algorithmDirective declares the algorithm of the un-prefixed hashes which follow it in a hash file,
+// ie: # algorithm: sha1
+const algorithmDirective = "# algorithm:"
+
 type HashMatcher interface {
-	IsHashMatch(hash string) bool
+	GetHashMatch(digests Digests) (HashDatabaseEntry, bool)
 }
 
 type hashMatcher struct {
-	hashes map[string]struct{}
+	hashes map[HashAlgorithm]map[string]HashDatabaseEntry
 }
 
 func NewHashMatcherFromString(content string) (HashMatcher, error) {
 	scn := bufio.NewScanner(strings.NewReader(content))
-	return newHashMatcher(scn)
+	return newHashMatcher("default hashes", scn)
 }
 
 func NewHashMatcherFromFile(file string, defaults string) (HashMatcher, error) {
@@ -41,24 +45,59 @@ func NewHashMatcherFromFile(file string, defaults string) (HashMatcher, error) {
 	} else {
 		f, err := os.Open(file)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 		defer func(file *os.File) {
 			_ = file.Close()
 		}(f)
 		scn := bufio.NewScanner(f)
-		return newHashMatcher(scn)
+		return newHashMatcher(file, scn)
 	}
 }
 
-func newHashMatcher(scn *bufio.Scanner) (HashMatcher, error) {
-	hashes := map[string]struct{}{}
+// newHashMatcher reads the hashes of the named file, reporting the line of any hash whose algorithm
+// cannot be determined or which is not valid for it.  The algorithm of each hash is computed for
+// the content scanned.
+func newHashMatcher(name string, scn *bufio.Scanner) (HashMatcher, error) {
+	hashes := map[HashAlgorithm]map[string]HashDatabaseEntry{}
+	var declared HashAlgorithm
+	line := 0
 	for scn.Scan() {
+		line += 1
+		if strings.HasPrefix(scn.Text(), algorithmDirective) {
+			algorithm, _, err := ParseHash(strings.TrimSpace(strings.TrimPrefix(scn.Text(), algorithmDirective)) + ":")
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", name, line, err)
+			}
+			declared = algorithm
+			continue
+		}
 		if strings.HasPrefix(scn.Text(), "#") {
 			continue
 		}
-		parts := strings.SplitN(scn.Text(), " ", 2)
-		hashes[strings.TrimSpace(parts[0])] = struct{}{}
+		entry, ok := parseHashDatabaseEntry(scn.Text())
+		if !ok {
+			continue
+		}
+		if strings.Contains(entry.Hash, ":") || len(declared) == 0 {
+			algorithm, hash, err := ParseHash(entry.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", name, line, err)
+			}
+			entry.Algorithm = algorithm
+			entry.Hash = hash
+		} else {
+			entry.Algorithm = declared
+			entry.Hash = strings.ToLower(entry.Hash)
+			if err := validateHash(entry.Algorithm, entry.Hash); err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", name, line, err)
+			}
+		}
+		RequireHashAlgorithm(entry.Algorithm)
+		if _, ok := hashes[entry.Algorithm]; !ok {
+			hashes[entry.Algorithm] = map[string]HashDatabaseEntry{}
+		}
+		hashes[entry.Algorithm][entry.Hash] = entry
 	}
 	if err := scn.Err(); err != nil {
 		return nil, err
@@ -66,7 +105,61 @@ func newHashMatcher(scn *bufio.Scanner) (HashMatcher, error) {
 	return &hashMatcher{hashes: hashes}, nil
 }
 
-func (h *hashMatcher) IsHashMatch(hash string) bool {
-	_, match := h.hashes[hash]
-	return match
+// parseHashDatabaseEntry parses either an annotated, tab separated, hash file line or a sha256sum
+// style line, in which case the label and version are derived from the jar or directory name in
+// the source path.
+func parseHashDatabaseEntry(line string) (HashDatabaseEntry, bool) {
+	if columns := strings.Split(line, "\t"); len(columns) >= 5 {
+		entry := HashDatabaseEntry{
+			Hash:    strings.TrimSpace(columns[0]),
+			Label:   strings.TrimSpace(columns[1]),
+			Version: strings.TrimSpace(columns[2]),
+			Source:  strings.TrimSpace(strings.Join(columns[4:], "\t")),
+		}
+		for _, cve := range strings.Split(columns[3], ",") {
+			if cve = strings.TrimSpace(cve); len(cve) > 0 {
+				entry.CVEs = append(entry.CVEs, cve)
+			}
+		}
+		return entry, len(entry.Hash) > 0
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return HashDatabaseEntry{}, false
+	}
+	entry := HashDatabaseEntry{
+		Hash:   fields[0],
+		Source: strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), fields[0])),
+	}
+	coordinates := coordinatesFromSource(entry.Source)
+	entry.Label = coordinates.Label()
+	entry.Version = coordinates.Version
+	return entry, true
+}
+
+func coordinatesFromSource(source string) Coordinates {
+	var elements []string
+	for _, e := range strings.Split(strings.ReplaceAll(source, pathSeparator, "/"), "/") {
+		if len(e) > 0 && e != "." {
+			elements = append(elements, e)
+		}
+	}
+	for i := len(elements) - 1; i >= 0; i-- {
+		if strings.HasSuffix(elements[i], ".jar") {
+			return CoordinatesFromFilename(elements[i])
+		}
+	}
+	if len(elements) > 0 {
+		return splitNameVersion(elements[0])
+	}
+	return Coordinates{}
+}
+
+func (h *hashMatcher) GetHashMatch(digests Digests) (HashDatabaseEntry, bool) {
+	for _, algorithm := range HashAlgorithms {
+		if entry, match := h.hashes[algorithm][digests[algorithm]]; match {
+			return entry, true
+		}
+	}
+	return HashDatabaseEntry{}, false
 }
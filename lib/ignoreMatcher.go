@@ -0,0 +1,115 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bufio"
+	"github.com/bmatcuk/doublestar/v4"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const IgnoreFileName = ".log4shell-ignore"
+
+type ignoreRule struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// IgnoreMatcher applies the gitignore style patterns from an ignore file to paths beneath the
+// directory containing it.
+type IgnoreMatcher interface {
+	// Match returns whether any pattern matched path and, if so, whether the path is ignored.
+	Match(path string, isDir bool) (matched bool, ignored bool)
+}
+
+type ignoreMatcher struct {
+	base  string
+	rules []ignoreRule
+}
+
+func NewIgnoreMatcherFromFile(file string, base string) (IgnoreMatcher, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(f)
+	return NewIgnoreMatcher(f, base)
+}
+
+func NewIgnoreMatcher(r io.Reader, base string) (IgnoreMatcher, error) {
+	var rules []ignoreRule
+	scn := bufio.NewScanner(r)
+	for scn.Scan() {
+		line := strings.TrimRight(scn.Text(), " \t\r")
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if len(line) == 0 {
+			continue
+		}
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = "**/" + line
+		}
+		if !doublestar.ValidatePattern(line) {
+			continue
+		}
+		rule.pattern = line
+		rules = append(rules, rule)
+	}
+	if err := scn.Err(); err != nil {
+		return nil, err
+	}
+	return &ignoreMatcher{base: base, rules: rules}, nil
+}
+
+func (m *ignoreMatcher) Match(path string, isDir bool) (bool, bool) {
+	rel, err := filepath.Rel(m.base, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false, false
+	}
+	rel = filepath.ToSlash(rel)
+	matched := false
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if ok, _ := doublestar.Match(rule.pattern, rel); ok {
+			matched = true
+			ignored = !rule.negate
+		}
+	}
+	return matched, ignored
+}
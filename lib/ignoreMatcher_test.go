@@ -0,0 +1,63 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	m, err := NewIgnoreMatcher(strings.NewReader(strings.Join([]string{
+		"# comment",
+		"*.jar",
+		"!keep.jar",
+		"build/",
+		"/root.txt",
+		"docs/*.txt",
+		"\\!bang",
+	}, "\n")), "/base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path    string
+		isDir   bool
+		matched bool
+		ignored bool
+	}{
+		{"/base/app.jar", false, true, true},
+		{"/base/lib/app.jar", false, true, true},
+		{"/base/lib/keep.jar", false, true, false},
+		{"/base/build", true, true, true},
+		{"/base/src/build", true, true, true},
+		{"/base/build", false, false, false},
+		{"/base/root.txt", false, true, true},
+		{"/base/sub/root.txt", false, false, false},
+		{"/base/docs/a.txt", false, true, true},
+		{"/base/sub/docs/a.txt", false, false, false},
+		{"/base/!bang", false, true, true},
+		{"/base/other.txt", false, false, false},
+		{"/base", true, false, false},
+		{"/other/app.jar", false, false, false},
+	}
+	for _, test := range tests {
+		matched, ignored := m.Match(test.path, test.isDir)
+		if matched != test.matched || ignored != test.ignored {
+			t.Errorf("%s (dir %v): expected matched %v ignored %v, got matched %v ignored %v", test.path, test.isDir, test.matched, test.ignored, matched, ignored)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Component is a library, or runtime, found by a detector.  Components are inventoried whether or
+// not they matched.
+type Component struct {
+	Kind    string
+	Name    string
+	Vendor  string
+	Version string
+	// Home is the installation directory of a runtime.
+	Home       string
+	Advisories []Advisory
+}
+
+func (c Component) String() string {
+	name := c.Name
+	if len(c.Vendor) > 0 {
+		name = fmt.Sprintf("%s %s", c.Vendor, name)
+	}
+	if len(c.Version) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s %s", name, c.Version)
+}
+
+// InventoryItem is a component and where it was found.
+type InventoryItem struct {
+	Component
+	FileId string
+	Path   []string
+}
+
+// AddComponents inventories the components found in the file.
+func (s *ScanResult) AddComponents(id string, path []string, components ...Component) {
+	for _, c := range components {
+		s.inventory = append(s.inventory, InventoryItem{Component: c, FileId: id, Path: path})
+	}
+}
+
+// GetInventory returns the components of the kind, or of every kind when kind is empty, ordered by
+// file.
+func (s *ScanResult) GetInventory(kind string) []InventoryItem {
+	var items []InventoryItem
+	for _, i := range s.inventory {
+		if len(kind) == 0 || i.Kind == kind {
+			items = append(items, i)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].FileId < items[j].FileId
+	})
+	return items
+}
+
+// fileLocation is where a file, identified relative to its root, was found.
+type fileLocation struct {
+	root     string
+	filename string
+}
+
+// AddLocation records the path of the file and the root it was found under.  Files whose id is not
+// relative to a root, such as scanned paths and symlinks, have no root.
+func (s *ScanResult) AddLocation(id string, filename string) {
+	root := ""
+	if id != filename && strings.HasSuffix(filename, string(filepath.Separator)+id) {
+		root = strings.TrimSuffix(filename, string(filepath.Separator)+id)
+	}
+	s.locations[id] = fileLocation{root: root, filename: filename}
+}
@@ -17,12 +17,12 @@ package lib
 
 import (
 	"fmt"
-	"github.com/Masterminds/semver"
 	"strings"
 )
 
 type JarNameMatcher interface {
 	IsMatch(filename string) (bool, error)
+	IsCoordinatesMatch(coordinates Coordinates) bool
 	AddMatchers(matchers ...string) error
 }
 
@@ -35,92 +35,68 @@ type jarNameMatcher struct {
 }
 
 func (m *jarNameMatcher) IsMatch(filename string) (bool, error) {
+	return m.IsCoordinatesMatch(CoordinatesFromFilename(filename)), nil
+}
+
+func (m *jarNameMatcher) IsCoordinatesMatch(coordinates Coordinates) bool {
+	if len(coordinates.Version) == 0 || !coordinates.IsCode() {
+		return false
+	}
+	version := ParseMavenVersion(coordinates.Version)
 	for _, m := range m.matchers {
-		match, err := m.isMatch(filename)
-		if err != nil {
-			return false, err
-		}
-		if match {
-			return true, nil
+		if m.isMatch(coordinates.ArtifactId, version) {
+			return true
 		}
 	}
-	return false, nil
+	return false
 }
 
+// AddMatchers adds matchers of the form artifactId/min/max, where the inclusive min and max
+// versions are optional.
 func (m *jarNameMatcher) AddMatchers(matchers ...string) error {
 	for _, j := range matchers {
 		parts := strings.SplitN(j, "/", 3)
-		if parts != nil {
-			name := parts[0]
-			if !strings.HasSuffix(name, "-") {
-				name = fmt.Sprintf("%s-", name)
-			}
-			var err error
-			var minSemver *semver.Version
-			var maxSemver *semver.Version
-			if len(parts) > 1 {
-				if len(parts[1]) > 0 {
-					minSemver, err = semver.NewVersion(parts[1])
-					if err != nil {
-						return fmt.Errorf("invalid minimum semantic version in %s: %v", j, err)
-					}
-				}
-			}
-			if len(parts) == 3 {
-				if len(parts[2]) > 0 {
-					maxSemver, err = semver.NewVersion(parts[2])
-					if err != nil {
-						return fmt.Errorf("invalid maximum semantic version in %s: %v", j, err)
-					}
-				}
-			}
-			m.matchers = append(m.matchers, matcher{
-				name:      name,
-				minSemver: minSemver,
-				maxSemver: maxSemver,
-			})
+		name := strings.TrimSuffix(parts[0], "-")
+		if len(name) == 0 {
+			return fmt.Errorf("missing artifactId in %s", j)
+		}
+		var minVersion *MavenVersion
+		var maxVersion *MavenVersion
+		if len(parts) > 1 && len(parts[1]) > 0 {
+			v := ParseMavenVersion(parts[1])
+			minVersion = &v
+		}
+		if len(parts) == 3 && len(parts[2]) > 0 {
+			v := ParseMavenVersion(parts[2])
+			maxVersion = &v
 		}
+		if minVersion != nil && maxVersion != nil && minVersion.Compare(*maxVersion) > 0 {
+			return fmt.Errorf("minimum version is greater than maximum version in %s", j)
+		}
+		m.matchers = append(m.matchers, matcher{
+			name:       name,
+			minVersion: minVersion,
+			maxVersion: maxVersion,
+		})
 	}
 	return nil
 }
 
 type matcher struct {
-	name      string
-	minSemver *semver.Version
-	maxSemver *semver.Version
+	name       string
+	minVersion *MavenVersion
+	maxVersion *MavenVersion
 }
 
-func (m *matcher) isMatch(filename string) (bool, error) {
-	if strings.HasPrefix(filename, m.name) {
-		filename = fileNameWithoutExtension(filename)
-		if m.minSemver != nil || m.maxSemver != nil {
-			ver := filename[len(m.name):]
-			semVersion, err := semver.NewVersion(ver)
-			if err != nil {
-				return false, err
-			}
-			if m.minSemver != nil && m.maxSemver != nil {
-				if semVersion.Compare(m.minSemver) >= 0 && semVersion.Compare(m.maxSemver) <= 0 {
-					return true, nil
-				}
-			} else if m.minSemver != nil {
-				if semVersion.Compare(m.minSemver) >= 0 {
-					return true, nil
-				}
-
-			} else if m.maxSemver != nil {
-				if semVersion.Compare(m.maxSemver) <= 0 {
-					return true, nil
-				}
-			}
-		}
+func (m *matcher) isMatch(artifactId string, version MavenVersion) bool {
+	if artifactId != m.name {
+		return false
 	}
-	return false, nil
-}
-
-func fileNameWithoutExtension(fileName string) string {
-	if pos := strings.LastIndexByte(fileName, '.'); pos != -1 {
-		return fileName[:pos]
+	if m.minVersion != nil && version.Compare(*m.minVersion) < 0 {
+		return false
+	}
+	if m.maxVersion != nil && version.Compare(*m.maxVersion) > 0 {
+		return false
 	}
-	return fileName
+	return true
 }
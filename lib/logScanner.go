@@ -0,0 +1,252 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxLogLineSize is the longest log line that is scanned, longer lines fail the file.
+const maxLogLineSize = 16 << 20
+
+// DefaultLogGlobs match the base names of files scanned when walking directories for logs.
+var DefaultLogGlobs = []string{"*.log", "*.log.*", "*.gz", "*.txt", "*_log", "*_log.*", "*.out", "*.out.*", "messages*", "syslog*"}
+
+type logTimestampFormat struct {
+	pattern   *regexp.Regexp
+	normalize func(string) string
+	layouts   []string
+}
+
+var logTimestampFormats = []logTimestampFormat{
+	{
+		pattern: regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`),
+		normalize: func(value string) string {
+			return strings.Replace(strings.Replace(value, ",", ".", 1), " ", "T", 1)
+		},
+		layouts: []string{"2006-01-02T15:04:05.999999999Z07:00", "2006-01-02T15:04:05.999999999Z0700", "2006-01-02T15:04:05.999999999"},
+	},
+	{
+		pattern: regexp.MustCompile(`\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}`),
+		layouts: []string{"02/Jan/2006:15:04:05 -0700"},
+	},
+	{
+		pattern: regexp.MustCompile(`^[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}`),
+		layouts: []string{time.Stamp},
+	},
+}
+
+// LogFinding is a jndi payload found on a line of a log file.
+type LogFinding struct {
+	File      string     `json:"file"`
+	Line      int        `json:"line"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	JndiPayload
+}
+
+func (f LogFinding) String() string {
+	timestamp := ""
+	if f.Timestamp != nil {
+		timestamp = fmt.Sprintf(" %s", f.Timestamp.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s:%d%s %s", f.File, f.Line, timestamp, f.Deobfuscated)
+}
+
+type LogScanResult struct {
+	FilesScanned int             `json:"filesScanned"`
+	Findings     []LogFinding    `json:"findings"`
+	Failures     []ReportFailure `json:"failures"`
+}
+
+// Callbacks returns the distinct callback hosts of the findings with the number of findings for each.
+func (r LogScanResult) Callbacks() map[string]int {
+	callbacks := map[string]int{}
+	for _, f := range r.Findings {
+		if callback := f.Callback(); len(callback) > 0 {
+			callbacks[callback]++
+		}
+	}
+	return callbacks
+}
+
+type LogScanner interface {
+	Scan(paths ...string) (LogScanResult, error)
+}
+
+type logScanner struct {
+	globMatcher GlobMatcher
+	logGlobs    []string
+	console     Console
+	now         time.Time
+}
+
+// NewLogScanner creates a LogScanner which scans paths given explicitly and, within directories,
+// files whose base name matches one of the logGlobs.  Gzip compressed logs are decompressed.
+func NewLogScanner(globMatcher GlobMatcher, logGlobs []string, verbosity int) (LogScanner, error) {
+	for _, g := range logGlobs {
+		if _, err := filepath.Match(g, ""); err != nil {
+			return nil, fmt.Errorf("invalid log glob: %s", g)
+		}
+	}
+	return &logScanner{
+		globMatcher: globMatcher,
+		logGlobs:    logGlobs,
+		console:     NewConsole(verbosity),
+		now:         time.Now(),
+	}, nil
+}
+
+func (s *logScanner) Scan(paths ...string) (LogScanResult, error) {
+	result := LogScanResult{Findings: []LogFinding{}, Failures: []ReportFailure{}}
+	walker, err := NewWalker(s.globMatcher, WalkOptions{MaxDepth: -1})
+	if err != nil {
+		return result, err
+	}
+	explicit := map[string]struct{}{}
+	for _, p := range paths {
+		if abs, err := AbsolutePath(p); err == nil {
+			explicit[abs] = struct{}{}
+		}
+	}
+	err = walker.WalkDirs(func(fileId string, filePath string, _ fs.FileMode, progress Progress) error {
+		if _, ok := explicit[filePath]; !ok && !s.isLogFile(filePath) {
+			return nil
+		}
+		if len(fileId) == 0 || fileId == "." {
+			fileId = filePath
+		}
+		result.FilesScanned++
+		findings, err := s.scanFile(fileId, filePath)
+		if err != nil {
+			result.Failures = append(result.Failures, ReportFailure{Id: fileId, Messages: []string{err.Error()}})
+			s.console.Error(progress, fileId)
+		} else if len(findings) > 0 {
+			result.Findings = append(result.Findings, findings...)
+			s.console.Matched(progress, fileId)
+		} else {
+			s.console.NotMatched(progress, fileId)
+		}
+		return nil
+	}, paths...)
+	return result, err
+}
+
+func (s *logScanner) isLogFile(filePath string) bool {
+	base := filepath.Base(filePath)
+	for _, g := range s.logGlobs {
+		if ok, _ := filepath.Match(g, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *logScanner) scanFile(fileId string, filePath string) ([]LogFinding, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(f)
+	reader, err := openLog(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []LogFinding
+	lineNumber := 0
+	scn := bufio.NewScanner(reader)
+	scn.Buffer(make([]byte, 64*1024), maxLogLineSize)
+	for scn.Scan() {
+		lineNumber++
+		line := scn.Text()
+		if !strings.Contains(line, "${") && !strings.Contains(line, "%") && !base64Token.MatchString(line) {
+			continue
+		}
+		payloads := FindJndiPayloads(line)
+		if len(payloads) == 0 {
+			continue
+		}
+		timestamp := s.parseTimestamp(line)
+		for _, p := range payloads {
+			findings = append(findings, LogFinding{File: fileId, Line: lineNumber, Timestamp: timestamp, JndiPayload: p})
+		}
+	}
+	if err := scn.Err(); err != nil {
+		return findings, fmt.Errorf("failed to read line %d: %v", lineNumber+1, err)
+	}
+	return findings, nil
+}
+
+// openLog returns a buffered reader of the log, decompressing it while it is gzip compressed.  Unlike
+// a ContentFileReader, it does not compute the digests of the content, which logs are not matched by.
+func openLog(r io.Reader) (io.Reader, error) {
+	reader := bufio.NewReader(r)
+	for {
+		header, err := reader.Peek(262)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if !isGzipHeader(header) {
+			return reader, nil
+		}
+		uncompressedStream, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open gzip file: %v", err)
+		}
+		reader = bufio.NewReader(uncompressedStream)
+	}
+}
+
+// parseTimestamp finds an ISO 8601, common log format or syslog timestamp near the start of the line.
+// Syslog timestamps lack a year, so they are assumed to be from the last year, counting back from
+// when the scan started, in which the date has passed.
+func (s *logScanner) parseTimestamp(line string) *time.Time {
+	if len(line) > 128 {
+		line = line[:128]
+	}
+	for _, format := range logTimestampFormats {
+		value := format.pattern.FindString(line)
+		if len(value) == 0 {
+			continue
+		}
+		if format.normalize != nil {
+			value = format.normalize(value)
+		}
+		for _, layout := range format.layouts {
+			if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				if t.Year() == 0 {
+					t = time.Date(s.now.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+					if t.After(s.now) {
+						t = t.AddDate(-1, 0, 0)
+					}
+				}
+				return &t
+			}
+		}
+	}
+	return nil
+}
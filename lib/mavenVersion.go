@@ -0,0 +1,268 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"strings"
+)
+
+// MavenVersion is a version ordered the way maven's ComparableVersion orders them, ie:
+// 2.0-alpha1 < 2.0-beta9 < 2.0-rc1 < 2.0-SNAPSHOT < 2.0 = 2.0.0 = 2.0-ga < 2.0-sp1 < 2.0.redhat-1 < 2.0.1
+type MavenVersion struct {
+	value string
+	items versionList
+}
+
+// ParseMavenVersion parses any string as a version, unrecognized text is treated as a qualifier.
+func ParseMavenVersion(version string) MavenVersion {
+	return MavenVersion{value: version, items: parseVersionItems(strings.ToLower(version))}
+}
+
+func (v MavenVersion) String() string {
+	return v.value
+}
+
+// Compare returns -1, 0 or 1 when v is older than, equivalent to, or newer than other.
+func (v MavenVersion) Compare(other MavenVersion) int {
+	return v.items.compare(other.items)
+}
+
+var (
+	versionQualifiers       = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+	versionQualifierAliases = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+	releaseQualifierIndex   = comparableQualifier("")
+)
+
+// versionItem is an int, a string qualifier or a nested list of items.  A nil item compares as
+// the absence of an item.
+type versionItem interface {
+	compare(other versionItem) int
+	isNull() bool
+}
+
+type versionInt string
+
+type versionString string
+
+type versionList []versionItem
+
+func newVersionInt(digits string) versionInt {
+	digits = strings.TrimLeft(digits, "0")
+	return versionInt(digits)
+}
+
+func (i versionInt) isNull() bool {
+	return len(i) == 0
+}
+
+func (i versionInt) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case versionInt:
+		if len(i) != len(o) {
+			return compareInts(len(i), len(o))
+		}
+		return strings.Compare(string(i), string(o))
+	}
+	return 1
+}
+
+func newVersionString(value string, followedByDigit bool) versionString {
+	if followedByDigit && len(value) == 1 {
+		switch value[0] {
+		case 'a':
+			value = "alpha"
+		case 'b':
+			value = "beta"
+		case 'm':
+			value = "milestone"
+		}
+	}
+	if alias, ok := versionQualifierAliases[value]; ok {
+		value = alias
+	}
+	return versionString(value)
+}
+
+// comparableQualifier orders the known qualifiers before any unknown ones, which are ordered
+// lexically.
+func comparableQualifier(qualifier string) string {
+	for i, q := range versionQualifiers {
+		if q == qualifier {
+			return string(rune('0' + i))
+		}
+	}
+	return string(rune('0'+len(versionQualifiers))) + "-" + qualifier
+}
+
+func (s versionString) isNull() bool {
+	return comparableQualifier(string(s)) == releaseQualifierIndex
+}
+
+func (s versionString) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		return strings.Compare(comparableQualifier(string(s)), releaseQualifierIndex)
+	case versionString:
+		return strings.Compare(comparableQualifier(string(s)), comparableQualifier(string(o)))
+	}
+	return -1
+}
+
+func (l versionList) isNull() bool {
+	return len(l) == 0
+}
+
+func (l versionList) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		if len(l) == 0 {
+			return 0
+		}
+		return l[0].compare(nil)
+	case versionInt:
+		return -1
+	case versionString:
+		return 1
+	case versionList:
+		for i := 0; i < len(l) || i < len(o); i++ {
+			var left, right versionItem
+			if i < len(l) {
+				left = l[i]
+			}
+			if i < len(o) {
+				right = o[i]
+			}
+			var result int
+			if left == nil {
+				if right != nil {
+					result = -right.compare(nil)
+				}
+			} else {
+				result = left.compare(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
+
+// normalize removes trailing null items, such as the .0 of 1.0, so that equivalent versions
+// compare equal.
+func (l versionList) normalize() versionList {
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i].isNull() {
+			l = append(l[:i], l[i+1:]...)
+		} else if _, ok := l[i].(versionList); !ok {
+			break
+		}
+	}
+	return l
+}
+
+// versionListBuilder tracks the nesting of lists while parsing, as each '-' or transition between
+// digits and letters starts a new sub-list.
+type versionListBuilder struct {
+	items    versionList
+	children []*versionListBuilder
+}
+
+func (b *versionListBuilder) add(item versionItem) {
+	b.items = append(b.items, item)
+	b.children = append(b.children, nil)
+}
+
+func (b *versionListBuilder) addList() *versionListBuilder {
+	child := &versionListBuilder{}
+	b.items = append(b.items, nil)
+	b.children = append(b.children, child)
+	return child
+}
+
+func (b *versionListBuilder) build() versionList {
+	for i, child := range b.children {
+		if child != nil {
+			b.items[i] = child.build()
+		}
+	}
+	return b.items.normalize()
+}
+
+func parseVersionItems(version string) versionList {
+	root := &versionListBuilder{}
+	list := root
+	isDigit := false
+	start := 0
+	parseItem := func(end int) versionItem {
+		if isDigit {
+			return newVersionInt(version[start:end])
+		}
+		return newVersionString(version[start:end], false)
+	}
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.':
+			if i == start {
+				list.add(versionInt(""))
+			} else {
+				list.add(parseItem(i))
+			}
+			start = i + 1
+		case c == '-':
+			if i == start {
+				list.add(versionInt(""))
+			} else {
+				list.add(parseItem(i))
+			}
+			start = i + 1
+			list = list.addList()
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				list.add(newVersionString(version[start:i], true))
+				start = i
+				list = list.addList()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				list.add(parseItem(i))
+				start = i
+				list = list.addList()
+			}
+			isDigit = false
+		}
+	}
+	if len(version) > start {
+		list.add(parseItem(len(version)))
+	}
+	return root.build()
+}
+
+func compareInts(a int, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"github.com/jwalton/gchalk"
+	"sort"
+	"strings"
+)
+
+// MitigationSetting is a setting which enables, or explicitly disables, a mitigation, such as
+// formatMsgNoLookups=true in a setenv.sh.
+type MitigationSetting struct {
+	Description string
+	Enabled     bool
+}
+
+// ScanMitigation is a file with mitigation settings.  Mitigations are not matches, so they are
+// neither counted as matched files nor compared by diff.
+type ScanMitigation struct {
+	fileId   string
+	path     []string
+	settings []MitigationSetting
+}
+
+func (m ScanMitigation) FileId() string {
+	return m.fileId
+}
+
+func (m ScanMitigation) Path() []string {
+	return m.path
+}
+
+func (m ScanMitigation) Settings() []MitigationSetting {
+	return m.settings
+}
+
+// Enabled reports whether the last setting of the file enables the mitigation, as later settings
+// override earlier ones.
+func (m ScanMitigation) Enabled() bool {
+	return len(m.settings) > 0 && m.settings[len(m.settings)-1].Enabled
+}
+
+func (m ScanMitigation) String() string {
+	descriptions := make([]string, len(m.settings))
+	for i, s := range m.settings {
+		descriptions[i] = s.Description
+	}
+	state := gchalk.BrightGreen("ENABLED")
+	if !m.Enabled() {
+		state = gchalk.Yellow("DISABLED")
+	}
+	return fmt.Sprintf("(%s) %s %s", state, m.fileId, gchalk.Grey(fmt.Sprintf("[%s]", strings.Join(descriptions, ", "))))
+}
+
+// AddMitigations records the mitigation settings found in the file.
+func (s *ScanResult) AddMitigations(id string, path []string, settings ...MitigationSetting) {
+	if len(settings) == 0 {
+		return
+	}
+	mitigation, ok := s.mitigations[id]
+	if !ok {
+		mitigation = ScanMitigation{fileId: id, path: path}
+	}
+	mitigation.settings = append(mitigation.settings, settings...)
+	s.mitigations[id] = mitigation
+}
+
+// GetMitigations returns the files with mitigation settings ordered by file.
+func (s *ScanResult) GetMitigations() []ScanMitigation {
+	mitigations := make([]ScanMitigation, 0, len(s.mitigations))
+	for _, m := range s.mitigations {
+		mitigations = append(mitigations, m)
+	}
+	sort.SliceStable(mitigations, func(i, j int) bool {
+		return mitigations[i].fileId < mitigations[j].fileId
+	})
+	return mitigations
+}
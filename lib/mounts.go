@@ -0,0 +1,88 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const mountInfoPath = "/proc/self/mountinfo"
+
+// LoadMounts returns the filesystem type of each mount point listed in /proc/self/mountinfo.  On
+// platforms without mountinfo an empty map is returned.
+func LoadMounts() (map[string]string, error) {
+	f, err := os.Open(mountInfoPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(f)
+	return ParseMountInfo(f)
+}
+
+func ParseMountInfo(r io.Reader) (map[string]string, error) {
+	mounts := map[string]string{}
+	scn := bufio.NewScanner(r)
+	for scn.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+		fields := strings.Fields(scn.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		separator := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				separator = i
+				break
+			}
+		}
+		if separator < 0 || separator+1 >= len(fields) {
+			continue
+		}
+		mounts[unescapeMountPath(fields[4])] = fields[separator+1]
+	}
+	if err := scn.Err(); err != nil {
+		return nil, err
+	}
+	return mounts, nil
+}
+
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, "\\") {
+		return path
+	}
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if c, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				sb.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		sb.WriteByte(path[i])
+	}
+	return sb.String()
+}
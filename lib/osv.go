@@ -0,0 +1,262 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const osvMavenEcosystem = "Maven"
+
+// Advisory is a vulnerability affecting a range of versions of a maven artifact.
+type Advisory struct {
+	Id       string
+	Aliases  []string
+	Summary  string
+	Package  Coordinates
+	ranges   []advisoryRange
+	versions map[string]struct{}
+}
+
+type advisoryRange []osvEvent
+
+func (a Advisory) String() string {
+	if len(a.Aliases) == 0 {
+		return a.Id
+	}
+	return fmt.Sprintf("%s (%s)", a.Id, strings.Join(a.Aliases, ", "))
+}
+
+// IsAffected reports whether the version of the artifact is listed by, or falls within one of the
+// ranges of, the advisory.
+func (a Advisory) IsAffected(version string) bool {
+	if _, ok := a.versions[version]; ok {
+		return true
+	}
+	v := ParseMavenVersion(version)
+	for _, r := range a.ranges {
+		if r.isAffected(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// newAdvisoryRange returns the events of an OSV range sorted by version, as the events of a range
+// are not required to be listed in order.  An introduced version of "0" sorts before all others.
+func newAdvisoryRange(events []osvEvent) advisoryRange {
+	r := make(advisoryRange, len(events))
+	copy(r, events)
+	sort.SliceStable(r, func(i, j int) bool {
+		if r[i].Introduced == "0" || r[j].Introduced == "0" {
+			return r[i].Introduced == "0" && r[j].Introduced != "0"
+		}
+		return ParseMavenVersion(r[i].version()).Compare(ParseMavenVersion(r[j].version())) < 0
+	})
+	return r
+}
+
+func (r advisoryRange) isAffected(v MavenVersion) bool {
+	affected := false
+	limited, belowLimit := false, false
+	for _, e := range r {
+		switch {
+		case len(e.Introduced) > 0:
+			if e.Introduced == "0" || v.Compare(ParseMavenVersion(e.Introduced)) >= 0 {
+				affected = true
+			}
+		case len(e.Fixed) > 0:
+			if v.Compare(ParseMavenVersion(e.Fixed)) >= 0 {
+				affected = false
+			}
+		case len(e.LastAffected) > 0:
+			if v.Compare(ParseMavenVersion(e.LastAffected)) > 0 {
+				affected = false
+			}
+		case len(e.Limit) > 0:
+			limited = true
+			if e.Limit == "*" || v.Compare(ParseMavenVersion(e.Limit)) < 0 {
+				belowLimit = true
+			}
+		}
+	}
+	return affected && (!limited || belowLimit)
+}
+
+type osvRecord struct {
+	Id        string        `json:"id"`
+	Aliases   []string      `json:"aliases"`
+	Summary   string        `json:"summary"`
+	Withdrawn string        `json:"withdrawn"`
+	Affected  []osvAffected `json:"affected"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges []struct {
+		Type   string     `json:"type"`
+		Events []osvEvent `json:"events"`
+	} `json:"ranges"`
+	Versions []string `json:"versions"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+	Limit        string `json:"limit"`
+}
+
+func (e osvEvent) version() string {
+	switch {
+	case len(e.Introduced) > 0:
+		return e.Introduced
+	case len(e.Fixed) > 0:
+		return e.Fixed
+	case len(e.LastAffected) > 0:
+		return e.LastAffected
+	default:
+		return e.Limit
+	}
+}
+
+// LoadOSVAdvisories reads the maven advisories from OSV JSON records.  Each path may be a single
+// record or a directory, such as an unpacked OSV ecosystem export, which is searched for records.
+func LoadOSVAdvisories(paths ...string) ([]Advisory, error) {
+	var advisories []Advisory
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+				return nil
+			}
+			a, err := loadOSVRecord(file)
+			if err != nil {
+				return fmt.Errorf("failed to load %s: %v", file, err)
+			}
+			advisories = append(advisories, a...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return advisories, nil
+}
+
+func loadOSVRecord(file string) ([]Advisory, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var record osvRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	if len(record.Withdrawn) > 0 {
+		return nil, nil
+	}
+	var advisories []Advisory
+	for _, affected := range record.Affected {
+		if affected.Package.Ecosystem != osvMavenEcosystem {
+			continue
+		}
+		parts := strings.SplitN(affected.Package.Name, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid maven package name: %s", affected.Package.Name)
+		}
+		advisory := Advisory{
+			Id:       record.Id,
+			Aliases:  record.Aliases,
+			Summary:  record.Summary,
+			Package:  Coordinates{GroupId: parts[0], ArtifactId: parts[1]},
+			versions: map[string]struct{}{},
+		}
+		for _, v := range affected.Versions {
+			advisory.versions[v] = struct{}{}
+		}
+		for _, r := range affected.Ranges {
+			if r.Type == "ECOSYSTEM" || r.Type == "SEMVER" {
+				advisory.ranges = append(advisory.ranges, newAdvisoryRange(r.Events))
+			}
+		}
+		advisories = append(advisories, advisory)
+	}
+	return advisories, nil
+}
+
+type AdvisoryMatcher interface {
+	// Match returns the advisories affecting the artifact.  Coordinates without a groupId, such as
+	// those derived from a file name, are matched by artifactId alone.
+	Match(coordinates Coordinates) []Advisory
+}
+
+type advisoryMatcher struct {
+	advisories map[string][]Advisory
+}
+
+func NewAdvisoryMatcher(advisories ...Advisory) AdvisoryMatcher {
+	byArtifactId := map[string][]Advisory{}
+	for _, a := range advisories {
+		byArtifactId[a.Package.ArtifactId] = append(byArtifactId[a.Package.ArtifactId], a)
+	}
+	return &advisoryMatcher{advisories: byArtifactId}
+}
+
+func (m *advisoryMatcher) Match(coordinates Coordinates) []Advisory {
+	if len(coordinates.Version) == 0 || !coordinates.IsCode() {
+		return nil
+	}
+	var matches []Advisory
+	for _, a := range m.advisories[coordinates.ArtifactId] {
+		if len(coordinates.GroupId) > 0 && coordinates.GroupId != a.Package.GroupId {
+			continue
+		}
+		if a.IsAffected(coordinates.Version) {
+			matches = append(matches, a)
+		}
+	}
+	return matches
+}
+
+// MergeAdvisories returns the union of the advisories, by id, sorted by id.
+func MergeAdvisories(advisories ...[]Advisory) []Advisory {
+	var merged []Advisory
+	seen := map[string]struct{}{}
+	for _, l := range advisories {
+		for _, a := range l {
+			if _, ok := seen[a.Id]; !ok {
+				seen[a.Id] = struct{}{}
+				merged = append(merged, a)
+			}
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Id < merged[j].Id
+	})
+	return merged
+}
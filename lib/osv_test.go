@@ -0,0 +1,117 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// GHSA-jfh8-c2jp-5v3q (CVE-2021-44228), as published in the OSV maven export.
+const log4ShellAdvisory = `{
+  "id": "GHSA-jfh8-c2jp-5v3q",
+  "aliases": ["CVE-2021-44228"],
+  "summary": "Remote code injection in Log4j",
+  "affected": [
+    {
+      "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+      "ranges": [
+        {"type": "ECOSYSTEM", "events": [{"introduced": "2.13.0"}, {"fixed": "2.15.0"}]},
+        {"type": "ECOSYSTEM", "events": [{"introduced": "2.0-beta9"}, {"fixed": "2.3.1"}]},
+        {"type": "ECOSYSTEM", "events": [{"introduced": "2.4"}, {"fixed": "2.12.2"}]}
+      ]
+    }
+  ]
+}`
+
+// The same ranges listed as a single range whose events are not in version order, bounded by a limit.
+const unorderedAdvisory = `{
+  "id": "GHSA-unordered",
+  "affected": [
+    {
+      "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+      "ranges": [
+        {
+          "type": "ECOSYSTEM",
+          "events": [
+            {"fixed": "2.15.0"}, {"introduced": "2.4"}, {"limit": "2.14.0"}, {"fixed": "2.3.1"},
+            {"introduced": "2.13.0"}, {"fixed": "2.12.2"}, {"introduced": "2.0-beta9"}
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+func loadTestAdvisory(t *testing.T, record string) Advisory {
+	file := filepath.Join(t.TempDir(), "advisory.json")
+	if err := os.WriteFile(file, []byte(record), 0644); err != nil {
+		t.Fatal(err)
+	}
+	advisories, err := LoadOSVAdvisories(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(advisories) != 1 {
+		t.Fatalf("expected 1 advisory, got %d", len(advisories))
+	}
+	return advisories[0]
+}
+
+func TestAdvisoryIsAffected(t *testing.T) {
+	log4Shell := loadTestAdvisory(t, log4ShellAdvisory)
+	unordered := loadTestAdvisory(t, unorderedAdvisory)
+	tests := []struct {
+		version   string
+		log4Shell bool
+		unordered bool
+	}{
+		{"2.0-beta8", false, false},
+		{"2.0-beta9", true, true},
+		{"2.0", true, true},
+		{"2.3", true, true},
+		{"2.3.1", false, false},
+		{"2.3.2", false, false},
+		{"2.4", true, true},
+		{"2.11.1", true, true},
+		{"2.12.1", true, true},
+		{"2.12.2", false, false},
+		{"2.12.4", false, false},
+		{"2.13.0", true, true},
+		{"2.14.1", true, false},
+		{"2.15.0", false, false},
+		{"2.17.1", false, false},
+	}
+	for _, test := range tests {
+		if affected := log4Shell.IsAffected(test.version); affected != test.log4Shell {
+			t.Errorf("%s: %s: expected affected %v, got %v", log4Shell.Id, test.version, test.log4Shell, affected)
+		}
+		if affected := unordered.IsAffected(test.version); affected != test.unordered {
+			t.Errorf("%s: %s: expected affected %v, got %v", unordered.Id, test.version, test.unordered, affected)
+		}
+	}
+}
+
+func TestAdvisoryLastAffected(t *testing.T) {
+	r := newAdvisoryRange([]osvEvent{{LastAffected: "1.2.17"}, {Introduced: "0"}})
+	tests := map[string]bool{"1.0": true, "1.2.17": true, "1.2.18": false}
+	for version, expected := range tests {
+		if affected := r.isAffected(ParseMavenVersion(version)); affected != expected {
+			t.Errorf("%s: expected affected %v, got %v", version, expected, affected)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// PackagingRole is the role of a nested archive, or class, within the application packaging it.
+type PackagingRole string
+
+const (
+	// BootLib is a jar in the BOOT-INF/lib of a Spring Boot jar, or WEB-INF/lib-provided of a Spring
+	// Boot war.
+	BootLib PackagingRole = "boot-lib"
+	// WebLib is a jar in the WEB-INF/lib of a war.
+	WebLib PackagingRole = "web-lib"
+	// EarModule is a module, or library, of an ear.
+	EarModule PackagingRole = "ear-module"
+	// NarBundle is a jar bundled in a NiFi nar.
+	NarBundle PackagingRole = "nar-bundle"
+	// ShadedClasses are classes of a library copied into a jar which is not that library.
+	ShadedClasses PackagingRole = "shaded-classes"
+)
+
+// Packaging is the role of a match within the application packaging it, which owns the fix.
+type Packaging struct {
+	Role        PackagingRole
+	Application Coordinates
+}
+
+func (p Packaging) String() string {
+	return fmt.Sprintf("%s of %s", p.Role, p.Application)
+}
+
+// packagedArchive is an archive which packages libraries, or classes, for an application.
+type packagedArchive struct {
+	filename    string
+	application Coordinates
+	declared    bool
+	packaging   *Packaging
+}
+
+// newPackagedArchive creates a packagedArchive whose application coordinates are those declared by
+// the manifest, or pom.properties, of the archive, falling back to those derived from the file name.
+// packaging is that of the archive itself, if any.
+func newPackagedArchive(reader ContentReader, packaging *Packaging) *packagedArchive {
+	archive := &packagedArchive{
+		filename:    reader.Filename(),
+		application: CoordinatesFromFilename(path.Base(strings.ReplaceAll(reader.Filename(), "\\", "/"))),
+		packaging:   packaging,
+	}
+	if indexed, ok := reader.(IndexedContentReader); ok {
+		archive.resolveApplication(indexed)
+	}
+	return archive
+}
+
+// resolveApplication reads the manifest and pom.properties entries ahead of the others, so that every
+// entry is labelled with the same application coordinates.  A manifest declaring an artifactId, such
+// as the Nar-Id of a NiFi nar, takes precedence over the pom.properties describing the archive.
+func (a *packagedArchive) resolveApplication(reader IndexedContentReader) {
+	var manifest *Coordinates
+	var pomCoordinates []Coordinates
+	for _, name := range reader.Names() {
+		if IsManifest(name) {
+			if c, err := parseIndexedEntry(reader, name, parseApplicationManifest); err == nil {
+				manifest = &c
+			}
+		} else if IsPomProperties(name) {
+			if c, err := parseIndexedEntry(reader, name, ParsePomProperties); err == nil {
+				pomCoordinates = append(pomCoordinates, c)
+			}
+		}
+	}
+	pom, hasPom := findPomCoordinates(path.Base(strings.ReplaceAll(a.filename, "\\", "/")), pomCoordinates)
+	if hasPom && (manifest == nil || len(manifest.ArtifactId) == 0) {
+		a.application = pom
+		a.declared = true
+		return
+	}
+	if manifest == nil {
+		return
+	}
+	if len(manifest.GroupId) > 0 {
+		a.application.GroupId = manifest.GroupId
+	}
+	if len(manifest.ArtifactId) > 0 {
+		a.application.ArtifactId = manifest.ArtifactId
+		a.declared = true
+	}
+	if len(manifest.Version) > 0 {
+		a.application.Version = manifest.Version
+	}
+}
+
+// parseIndexedEntry parses the named entry of the archive.
+func parseIndexedEntry(reader IndexedContentReader, name string, parse func(r io.Reader) (Coordinates, error)) (Coordinates, error) {
+	file, err := reader.Open(name)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	defer func(file ContentFile) {
+		_ = file.Close()
+	}(file)
+	return parse(file.Reader())
+}
+
+// parseApplicationManifest returns the coordinates declared by the manifest, including the Nar-Group,
+// Nar-Id and Nar-Version of NiFi nars.
+func parseApplicationManifest(r io.Reader) (Coordinates, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	manifest, err := ParseManifest(bytes.NewReader(content))
+	if err != nil {
+		return Coordinates{}, err
+	}
+	attributes, _ := parseManifestAttributes(bytes.NewReader(content))
+	if nar := attributes["Nar-Id"]; len(nar) > 0 {
+		manifest = Coordinates{GroupId: attributes["Nar-Group"], ArtifactId: nar, Version: attributes["Nar-Version"]}
+	}
+	return manifest, nil
+}
+
+// entryPackaging returns the packaging of an entry of the archive.  Entries without a role of their
+// own inherit that of the archive.
+func (a *packagedArchive) entryPackaging(name string) *Packaging {
+	if role, ok := a.entryRole(name); ok {
+		return &Packaging{Role: role, Application: a.application}
+	}
+	return a.packaging
+}
+
+func (a *packagedArchive) entryRole(name string) (PackagingRole, bool) {
+	extension := strings.ToLower(path.Ext(a.filename))
+	if strings.HasSuffix(name, ".class") {
+		// Coordinates guessed from the file name are too weak to judge a class as shaded.
+		if a.declared && isShadedClass(a.application, extension, name) {
+			return ShadedClasses, true
+		}
+		return "", false
+	}
+	switch {
+	case strings.HasPrefix(name, "BOOT-INF/lib/") || strings.HasPrefix(name, "WEB-INF/lib-provided/"):
+		return BootLib, true
+	case strings.HasPrefix(name, "WEB-INF/lib/"):
+		return WebLib, true
+	case extension == ".ear":
+		return EarModule, true
+	case extension == ".nar" || strings.HasPrefix(name, "META-INF/bundled-dependencies/"):
+		return NarBundle, true
+	}
+	return "", false
+}
+
+// isShadedClass reports whether the class is likely shaded into a jar, or war, built from another
+// artifact, which is judged by none of the packages of the class starting with the first word of the
+// artifactId.  So org/apache/logging/log4j/core/lookup/JndiLookup.class belongs in log4j-core-2.14.1.jar,
+// while it was shaded into my-service-1.0.jar.
+func isShadedClass(application Coordinates, extension string, name string) bool {
+	switch extension {
+	case ".jar", ".war":
+	default:
+		return false
+	}
+	word := strings.ToLower(strings.SplitN(application.ArtifactId, "-", 2)[0])
+	if len(word) == 0 {
+		return false
+	}
+	packages := strings.Split(strings.ToLower(path.Dir(name)), "/")
+	for _, p := range packages {
+		if strings.HasPrefix(p, word) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadPathsFromFile reads a list of paths from file, or from stdin when file is "-".  Paths
+// may be separated by newlines or, as produced by `find -print0`, by NUL characters.
+func ReadPathsFromFile(file string) ([]string, error) {
+	if file == "-" {
+		return ReadPaths(os.Stdin)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(f)
+	return ReadPaths(f)
+}
+
+func ReadPaths(r io.Reader) ([]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	separator := []byte("\n")
+	if bytes.IndexByte(content, 0) >= 0 {
+		separator = []byte{0}
+	}
+	var paths []string
+	for _, part := range bytes.Split(content, separator) {
+		path := strings.TrimSuffix(string(part), "\r")
+		if len(strings.TrimSpace(path)) == 0 {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,142 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Exit codes are stable and combine as a bitmask, ie: 6 means both matches and failures violated the
+// policy.
+const (
+	ExitCodeOk       = 0
+	ExitCodeError    = 1
+	ExitCodeMatches  = 2
+	ExitCodeFailures = 4
+)
+
+var DefaultFailOn = []string{
+	strings.ToLower(ClassName.String()),
+	strings.ToLower(ClassHash.String()),
+	strings.ToLower(JarName.String()),
+	strings.ToLower(JarHash.String()),
+	strings.ToLower(JarAdvisory.String()),
+	strings.ToLower(ConfigLookup.String()),
+	strings.ToLower(ContentPattern.String()),
+	strings.ToLower(Spring4shell.String()),
+	strings.ToLower(Text4shell.String()),
+	strings.ToLower(Logback.String()),
+	strings.ToLower(Log4j1.String()),
+}
+
+type Policy struct {
+	failOn         map[MatchType]struct{}
+	failOnSeverity Severity
+	ignoreFailures []*regexp.Regexp
+	maxFailures    int
+}
+
+type PolicyResult struct {
+	ViolatingMatches  []ScanMatch
+	ViolatingFailures []ScanFailure
+	IgnoredFailures   []ScanFailure
+	ExitCode          int
+}
+
+func NewPolicy(failOn []string, failOnSeverity string, ignoreFailuresMatching []string, maxFailures int) (Policy, error) {
+	policy := Policy{
+		failOn:      map[MatchType]struct{}{},
+		maxFailures: maxFailures,
+	}
+	for _, f := range failOn {
+		matchType, err := MatchTypeString(strings.TrimSpace(f))
+		if err != nil {
+			return policy, fmt.Errorf("%s is not one of %s", f, strings.ToLower(strings.Join(MatchTypeStrings(), ",")))
+		}
+		policy.failOn[matchType] = struct{}{}
+	}
+	severity, err := SeverityString(strings.TrimSpace(failOnSeverity))
+	if err != nil {
+		return policy, fmt.Errorf("%s is not one of %s", failOnSeverity, strings.Join(SeverityStrings(), ","))
+	}
+	policy.failOnSeverity = severity
+	for _, r := range ignoreFailuresMatching {
+		regex, err := regexp.Compile(r)
+		if err != nil {
+			return policy, fmt.Errorf("invalid failure regex %s: %v", r, err)
+		}
+		policy.ignoreFailures = append(policy.ignoreFailures, regex)
+	}
+	if maxFailures < 0 {
+		return policy, fmt.Errorf("max failures must not be negative: %d", maxFailures)
+	}
+	return policy, nil
+}
+
+// Evaluate determines which matches and failures in result violate the policy and the resulting
+// exit code.
+func (p Policy) Evaluate(result ScanResult) PolicyResult {
+	policyResult := PolicyResult{ExitCode: ExitCodeOk}
+	for _, m := range result.GetMatches() {
+		if p.isViolation(m) {
+			policyResult.ViolatingMatches = append(policyResult.ViolatingMatches, m)
+		}
+	}
+	for _, f := range result.GetFailures() {
+		if p.isIgnored(f) {
+			policyResult.IgnoredFailures = append(policyResult.IgnoredFailures, f)
+		} else {
+			policyResult.ViolatingFailures = append(policyResult.ViolatingFailures, f)
+		}
+	}
+	if len(policyResult.ViolatingMatches) > 0 {
+		policyResult.ExitCode |= ExitCodeMatches
+	}
+	if len(policyResult.ViolatingFailures) > p.maxFailures {
+		policyResult.ExitCode |= ExitCodeFailures
+	}
+	return policyResult
+}
+
+func (p Policy) isViolation(m ScanMatch) bool {
+	for _, matchType := range m.MatchTypes() {
+		if _, ok := p.failOn[matchType]; ok && matchType.Severity() >= p.failOnSeverity {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Policy) isIgnored(f ScanFailure) bool {
+	if len(p.ignoreFailures) == 0 {
+		return false
+	}
+	for _, message := range f.Messages() {
+		ignored := false
+		for _, regex := range p.ignoreFailures {
+			if regex.MatchString(fmt.Sprintf("%s: %s", f.FileId(), message)) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,278 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// Report is the JSON representation of a ScanResult.
+type Report struct {
+	ScannerVersion string             `json:"scannerVersion"`
+	Host           string             `json:"host,omitempty"`
+	Timestamp      time.Time          `json:"timestamp"`
+	Summary        ReportSummary      `json:"summary"`
+	Matches        []ReportMatch      `json:"matches"`
+	Suppressed     []ReportMatch      `json:"suppressed,omitempty"`
+	Mitigations    []ReportMitigation `json:"mitigations,omitempty"`
+	Inventory      []ReportComponent  `json:"inventory,omitempty"`
+	Failures       []ReportFailure    `json:"failures"`
+}
+
+type ReportSummary struct {
+	FilesScanned  int `json:"filesScanned"`
+	FilesSkipped  int `json:"filesSkipped"`
+	FilesFiltered int `json:"filesFiltered"`
+	FilesMatched  int `json:"filesMatched"`
+	ScanFailures  int `json:"scanFailures"`
+	ExitCode      int `json:"exitCode"`
+}
+
+type ReportMatch struct {
+	Id         string            `json:"id"`
+	Path       []string          `json:"path"`
+	Hash       string            `json:"hash,omitempty"`
+	Digests    map[string]string `json:"digests,omitempty"`
+	HashEntry  *ReportHashEntry  `json:"hashEntry,omitempty"`
+	Advisories []ReportAdvisory  `json:"advisories,omitempty"`
+	Repository *ReportRepository `json:"repository,omitempty"`
+	Findings   []string          `json:"findings,omitempty"`
+	Rules      []ReportRuleMatch `json:"rules,omitempty"`
+	Packaging  *ReportPackaging  `json:"packaging,omitempty"`
+	Recovered  bool              `json:"recovered,omitempty"`
+	Runtime    *ReportRuntime    `json:"runtime,omitempty"`
+	MatchTypes []string          `json:"matchTypes"`
+}
+
+// ReportPackaging is the role of a match within the application packaging it, and the coordinates of
+// that application.
+type ReportPackaging struct {
+	Role        string `json:"role"`
+	Application string `json:"application"`
+}
+
+// ReportRuntime is the java runtime nearest to a match.  TrustURLCodebase is whether the runtime
+// loads remote classes referenced by LDAP lookups by default, when known.
+type ReportRuntime struct {
+	Home             string `json:"home"`
+	Name             string `json:"name"`
+	Vendor           string `json:"vendor,omitempty"`
+	Version          string `json:"version,omitempty"`
+	TrustURLCodebase *bool  `json:"trustURLCodebase,omitempty"`
+}
+
+// ReportMitigation is a file with mitigation settings.  Enabled is whether its last setting enables
+// the mitigation.
+type ReportMitigation struct {
+	Id       string   `json:"id"`
+	Path     []string `json:"path"`
+	Enabled  bool     `json:"enabled"`
+	Settings []string `json:"settings"`
+}
+
+// ReportComponent is an inventoried component and where it was found.
+type ReportComponent struct {
+	Id         string           `json:"id"`
+	Path       []string         `json:"path"`
+	Kind       string           `json:"kind"`
+	Name       string           `json:"name"`
+	Vendor     string           `json:"vendor,omitempty"`
+	Version    string           `json:"version,omitempty"`
+	Home       string           `json:"home,omitempty"`
+	Advisories []ReportAdvisory `json:"advisories,omitempty"`
+}
+
+type ReportRuleMatch struct {
+	Id     string `json:"id"`
+	Offset int    `json:"offset"`
+}
+
+type ReportRepository struct {
+	Root        string `json:"root"`
+	Layout      string `json:"layout"`
+	Coordinates string `json:"coordinates"`
+}
+
+type ReportAdvisory struct {
+	Id      string   `json:"id"`
+	Aliases []string `json:"aliases,omitempty"`
+	Summary string   `json:"summary,omitempty"`
+	Package string   `json:"package"`
+}
+
+type ReportHashEntry struct {
+	Algorithm string   `json:"algorithm,omitempty"`
+	Label     string   `json:"label,omitempty"`
+	Version   string   `json:"version,omitempty"`
+	CVEs      []string `json:"cves,omitempty"`
+	Source    string   `json:"source,omitempty"`
+}
+
+type ReportFailure struct {
+	Id       string   `json:"id"`
+	Messages []string `json:"messages"`
+}
+
+func NewReport(result ScanResult, exitCode int, scannerVersion string, timestamp time.Time) Report {
+	host, _ := os.Hostname()
+	report := Report{
+		ScannerVersion: scannerVersion,
+		Host:           host,
+		Timestamp:      timestamp,
+		Summary: ReportSummary{
+			FilesScanned:  result.GetTotalFilesScanned(),
+			FilesSkipped:  result.GetTotalFilesSkipped(),
+			FilesFiltered: result.GetTotalFilesFiltered(),
+			FilesMatched:  result.GetTotalFilesMatched(),
+			ScanFailures:  result.GetTotalScanFailures(),
+			ExitCode:      exitCode,
+		},
+		Matches:  []ReportMatch{},
+		Failures: []ReportFailure{},
+	}
+	for _, m := range result.GetMatches() {
+		reportMatch := newReportMatch(m)
+		if r, ok := result.GetRuntime(m); ok {
+			reportMatch.Runtime = &ReportRuntime{Home: r.Home, Name: r.Name, Vendor: r.Vendor, Version: r.Version}
+			if trusts, known := JavaTrustsURLCodebase(r.Version); known {
+				reportMatch.Runtime.TrustURLCodebase = &trusts
+			}
+		}
+		report.Matches = append(report.Matches, reportMatch)
+	}
+	for _, m := range result.GetSuppressedMatches() {
+		report.Suppressed = append(report.Suppressed, newReportMatch(m.ScanMatch))
+	}
+	for _, m := range result.GetMitigations() {
+		mitigation := ReportMitigation{Id: m.FileId(), Path: m.Path(), Enabled: m.Enabled()}
+		for _, s := range m.Settings() {
+			mitigation.Settings = append(mitigation.Settings, s.Description)
+		}
+		report.Mitigations = append(report.Mitigations, mitigation)
+	}
+	for _, i := range result.GetInventory("") {
+		report.Inventory = append(report.Inventory, ReportComponent{
+			Id:         i.FileId,
+			Path:       i.Path,
+			Kind:       i.Kind,
+			Name:       i.Name,
+			Vendor:     i.Vendor,
+			Version:    i.Version,
+			Home:       i.Home,
+			Advisories: newReportAdvisories(i.Advisories),
+		})
+	}
+	for _, f := range result.GetFailures() {
+		report.Failures = append(report.Failures, ReportFailure{Id: f.FileId(), Messages: f.Messages()})
+	}
+	return report
+}
+
+func newReportMatch(m ScanMatch) ReportMatch {
+	matchTypes := MatchTypeNames(m.MatchTypes())
+	var hashEntry *ReportHashEntry
+	if e := m.HashEntry(); e != nil {
+		hashEntry = &ReportHashEntry{
+			Algorithm: string(e.Algorithm),
+			Label:     e.Label,
+			Version:   e.Version,
+			CVEs:      e.CVEs,
+			Source:    e.Source,
+		}
+	}
+	var digests map[string]string
+	if len(m.Digests()) > 0 {
+		digests = make(map[string]string, len(m.Digests()))
+		for algorithm, digest := range m.Digests() {
+			digests[string(algorithm)] = digest
+		}
+	}
+	var repository *ReportRepository
+	if r := m.Repository(); r != nil {
+		repository = &ReportRepository{Root: r.Root, Layout: string(r.Layout), Coordinates: r.Coordinates.String()}
+	}
+	var packaging *ReportPackaging
+	if p := m.Packaging(); p != nil {
+		packaging = &ReportPackaging{Role: string(p.Role), Application: p.Application.String()}
+	}
+	var rules []ReportRuleMatch
+	for _, r := range m.RuleMatches() {
+		rules = append(rules, ReportRuleMatch{Id: r.RuleId, Offset: r.Offset})
+	}
+	return ReportMatch{
+		Id:         m.FileId(),
+		Path:       m.Path(),
+		Hash:       m.Hash(),
+		Digests:    digests,
+		HashEntry:  hashEntry,
+		Advisories: newReportAdvisories(m.Advisories()),
+		Repository: repository,
+		Findings:   m.Findings(),
+		Rules:      rules,
+		Packaging:  packaging,
+		Recovered:  m.Recovered(),
+		MatchTypes: matchTypes,
+	}
+}
+
+func newReportAdvisories(advisories []Advisory) []ReportAdvisory {
+	var reportAdvisories []ReportAdvisory
+	for _, a := range advisories {
+		reportAdvisories = append(reportAdvisories, ReportAdvisory{Id: a.Id, Aliases: a.Aliases, Summary: a.Summary, Package: a.Package.Label()})
+	}
+	return reportAdvisories
+}
+
+// Key identifies the matched file across reports by its structured path, so that a file replaced in
+// place is compared with the file it replaced.
+func (m ReportMatch) Key() string {
+	return JoinPath(m.Path)
+}
+
+func (r Report) Write(w io.Writer) error {
+	return WriteJSON(w, r)
+}
+
+func (r Report) WriteFile(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func LoadReport(file string) (Report, error) {
+	report := Report{}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return report, err
+	}
+	err = json.Unmarshal(content, &report)
+	return report, err
+}
+
+func WriteJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,107 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type RepositoryLayout string
+
+const (
+	MavenRepository  RepositoryLayout = "maven"
+	GradleRepository RepositoryLayout = "gradle"
+)
+
+// Repository locates a file within a maven local repository or gradle dependency cache, along with
+// the coordinates encoded by its path.
+type Repository struct {
+	Root        string
+	Layout      RepositoryLayout
+	Coordinates Coordinates
+}
+
+// FindRepository recognises files within ~/.m2/repository, laid out as
+// <group path>/<artifactId>/<version>/<file>, and ~/.gradle/caches/modules-2/files-2.1, laid out as
+// <group>/<artifactId>/<version>/<sha1>/<file>.
+func FindRepository(filePath string) (Repository, bool) {
+	elements := strings.Split(filepath.ToSlash(filePath), "/")
+	for i := len(elements) - 2; i > 0; i-- {
+		root := strings.Join(elements[:i+1], "/")
+		rel := elements[i+1:]
+		if elements[i] == "repository" && elements[i-1] == ".m2" {
+			if coordinates, ok := CoordinatesFromMavenLayout(strings.Join(rel, "/")); ok {
+				return Repository{Root: filepath.FromSlash(root), Layout: MavenRepository, Coordinates: coordinates}, true
+			}
+		} else if elements[i] == "files-2.1" && elements[i-1] == "modules-2" && len(rel) == 5 {
+			coordinates := Coordinates{GroupId: rel[0], ArtifactId: rel[1], Version: rel[2]}
+			if classifier, ok := classifierFromFilename(rel[4], coordinates); ok {
+				coordinates.Classifier = classifier
+				return Repository{Root: filepath.FromSlash(root), Layout: GradleRepository, Coordinates: coordinates}, true
+			}
+		}
+	}
+	return Repository{}, false
+}
+
+// classifierFromFilename returns the classifier of an artifactId-version[-classifier].ext file name.
+func classifierFromFilename(filename string, coordinates Coordinates) (string, bool) {
+	prefix := coordinates.ArtifactId + "-" + coordinates.Version
+	if !strings.HasPrefix(filename, prefix) {
+		return "", false
+	}
+	rest := strings.TrimSuffix(filename[len(prefix):], path.Ext(filename))
+	if len(rest) == 0 {
+		return "", true
+	}
+	if !strings.HasPrefix(rest, "-") {
+		return "", false
+	}
+	return rest[1:], true
+}
+
+// RepositoryMatches are the matches found within a single maven local repository or gradle cache.
+type RepositoryMatches struct {
+	Root    string
+	Layout  RepositoryLayout
+	Matches []ScanMatch
+}
+
+// GetRepositoryMatches groups the matches found within repositories by repository, ordered by root.
+func (s *ScanResult) GetRepositoryMatches() []RepositoryMatches {
+	var repositories []RepositoryMatches
+	index := map[string]int{}
+	for _, m := range s.GetMatches() {
+		r := m.Repository()
+		if r == nil {
+			continue
+		}
+		i, ok := index[r.Root]
+		if !ok {
+			i = len(repositories)
+			index[r.Root] = i
+			repositories = append(repositories, RepositoryMatches{Root: r.Root, Layout: r.Layout})
+		}
+		repositories[i].Matches = append(repositories[i].Matches, m)
+	}
+	sort.SliceStable(repositories, func(i, j int) bool {
+		return repositories[i].Root < repositories[j].Root
+	})
+	return repositories
+}
@@ -18,6 +18,7 @@ package lib
 import (
 	"fmt"
 	"github.com/jwalton/gchalk"
+	"io/fs"
 	"sort"
 	"strings"
 )
@@ -26,16 +27,117 @@ type ScanFunc func(hashes map[string]struct{}, id string, source interface{}) (m
 
 type Scanner interface {
 	Scan(roots ...string) (ScanResult, error)
+	ScanPaths(paths ...string) (ScanResult, error)
 }
 
 type ScanMatch struct {
 	fileId     string
-	matchTypes []string
+	path       []string
+	digests    Digests
+	hashEntry  *HashDatabaseEntry
+	advisories []Advisory
+	repository *Repository
+	findings   []string
+	rules      []ContentRuleMatch
+	packaging  *Packaging
+	recovered  bool
+	matchTypes []MatchType
+}
+
+func (s ScanMatch) FileId() string {
+	return s.fileId
+}
+
+// Path returns the location of the match as the root relative file followed by the name of each
+// nested archive entry leading to it.
+func (s ScanMatch) Path() []string {
+	return s.path
+}
+
+// Hash returns the SHA256 digest of the matched content.
+func (s ScanMatch) Hash() string {
+	return s.digests[SHA256]
+}
+
+// Digests returns every digest computed for the matched content.
+func (s ScanMatch) Digests() Digests {
+	return s.digests
+}
+
+// HashEntry returns the hash database entry which matched, if any.
+func (s ScanMatch) HashEntry() *HashDatabaseEntry {
+	return s.hashEntry
+}
+
+// Advisories returns the advisories affecting the matched jar, if any.
+func (s ScanMatch) Advisories() []Advisory {
+	return s.advisories
+}
+
+// Repository returns the maven local repository or gradle cache containing the match, if any.
+func (s ScanMatch) Repository() *Repository {
+	return s.repository
+}
+
+// Findings describes what was found within the content of a configuration file or script.
+func (s ScanMatch) Findings() []string {
+	return s.findings
+}
+
+// RuleMatches returns the content rules which matched the file.
+func (s ScanMatch) RuleMatches() []ContentRuleMatch {
+	return s.rules
+}
+
+// Packaging returns the role of the match within the application packaging it, if any.
+func (s ScanMatch) Packaging() *Packaging {
+	return s.packaging
+}
+
+// Recovered reports whether the match was found within a corrupted zip whose entries were recovered
+// from their local file headers.
+func (s ScanMatch) Recovered() bool {
+	return s.recovered
+}
+
+func (s ScanMatch) MatchTypes() []MatchType {
+	return s.matchTypes
 }
 
 func (s ScanMatch) String() string {
-	return fmt.Sprintf("(%s) %s", strings.Join(s.matchTypes, " "),
-		gchalk.WithAnsi256(uint8(245+2*len(s.matchTypes))).Paint(s.fileId))
+	matchTypes := make([]string, len(s.matchTypes))
+	for i, m := range s.matchTypes {
+		matchTypes[i] = getMatchTypeString(m)
+	}
+	description := ""
+	if s.hashEntry != nil {
+		description = fmt.Sprintf(" %s", gchalk.Grey(fmt.Sprintf("[%s]", s.hashEntry.Description())))
+	}
+	if len(s.advisories) > 0 {
+		ids := make([]string, len(s.advisories))
+		for i, a := range s.advisories {
+			ids[i] = a.String()
+		}
+		description = fmt.Sprintf("%s %s", description, gchalk.Grey(fmt.Sprintf("[%s]", strings.Join(ids, ", "))))
+	}
+	if len(s.findings) > 0 {
+		description = fmt.Sprintf("%s %s", description, gchalk.Grey(fmt.Sprintf("[%s]", strings.Join(s.findings, ", "))))
+	}
+	if len(s.rules) > 0 {
+		rules := make([]string, len(s.rules))
+		for i, r := range s.rules {
+			rules[i] = r.String()
+		}
+		description = fmt.Sprintf("%s %s", description, gchalk.Grey(fmt.Sprintf("[%s]", strings.Join(rules, ", "))))
+	}
+	if s.packaging != nil {
+		description = fmt.Sprintf("%s %s", description, gchalk.Grey(fmt.Sprintf("[%s]", s.packaging)))
+	}
+	if s.recovered {
+		description = fmt.Sprintf("%s %s", description, gchalk.Grey("[recovered]"))
+	}
+	return fmt.Sprintf("(%s) %s%s", strings.Join(matchTypes, " "),
+		gchalk.WithAnsi256(uint8(245+2*len(s.matchTypes))).Paint(s.fileId), description)
 }
 
 type ScanFailure struct {
@@ -43,21 +145,61 @@ type ScanFailure struct {
 	messages []string
 }
 
+func (s ScanFailure) FileId() string {
+	return s.fileId
+}
+
+func (s ScanFailure) Messages() []string {
+	return s.messages
+}
+
 func (s ScanFailure) String() string {
 	return fmt.Sprintf("%s\n        %s", s.fileId, gchalk.Grey(strings.Join(s.messages, "        \n")))
 }
 
+type matchDetails struct {
+	path       []string
+	digests    Digests
+	hashEntry  *HashDatabaseEntry
+	advisories []Advisory
+	repository *Repository
+	findings   []string
+	rules      []ContentRuleMatch
+	packaging  *Packaging
+	recovered  bool
+}
+
 type ScanResult struct {
-	matches           map[string]map[MatchType]struct{}
-	failures          map[string]map[string]struct{}
-	totalFilesScanned int
+	matches            map[string]map[MatchType]struct{}
+	details            map[string]matchDetails
+	suppressed         map[string]SuppressedMatch
+	expired            []Suppression
+	failures           map[string]map[string]struct{}
+	skippedMounts      []string
+	ignoreFiles        []string
+	inventory          []InventoryItem
+	mitigations        map[string]ScanMitigation
+	locations          map[string]fileLocation
+	totalFilesScanned  int
+	totalFilesSkipped  int
+	totalFilesFiltered int
 }
 
 func NewScanResult() ScanResult {
 	return ScanResult{
-		matches:           map[string]map[MatchType]struct{}{},
-		failures:          map[string]map[string]struct{}{},
-		totalFilesScanned: 0,
+		matches:            map[string]map[MatchType]struct{}{},
+		details:            map[string]matchDetails{},
+		suppressed:         map[string]SuppressedMatch{},
+		expired:            []Suppression{},
+		failures:           map[string]map[string]struct{}{},
+		skippedMounts:      []string{},
+		ignoreFiles:        []string{},
+		inventory:          []InventoryItem{},
+		mitigations:        map[string]ScanMitigation{},
+		locations:          map[string]fileLocation{},
+		totalFilesScanned:  0,
+		totalFilesSkipped:  0,
+		totalFilesFiltered: 0,
 	}
 }
 
@@ -105,38 +247,80 @@ func (s *ScanResult) GetMatches() []ScanMatch {
 	for _, k := range fileIds {
 		v := s.matches[k]
 		if _, content := v[Content]; len(v) > 1 || !content {
-			j := 0
-			matchTypes := make([]string, len(v))
-			for m := range v {
-				matchTypes[j] = s.getMatchTypeString(m)
-				j += 1
-			}
-			sort.SliceStable(matchTypes, func(i, j int) bool {
-				return matchTypes[i] < matchTypes[j]
-			})
-			results[i] = ScanMatch{k, matchTypes}
+			results[i] = s.getMatch(k)
 			i += 1
 		}
 	}
 	return results[:i]
 }
 
-func (s *ScanResult) getMatchTypeString(m MatchType) string {
+func (s *ScanResult) getMatch(fileId string) ScanMatch {
+	v := s.matches[fileId]
+	j := 0
+	matchTypes := make([]MatchType, len(v))
+	for m := range v {
+		matchTypes[j] = m
+		j += 1
+	}
+	sort.SliceStable(matchTypes, func(i, j int) bool {
+		return matchTypes[i].String() < matchTypes[j].String()
+	})
+	details, ok := s.details[fileId]
+	if !ok {
+		details = matchDetails{path: []string{fileId}}
+	}
+	return ScanMatch{fileId, details.path, details.digests, details.hashEntry, details.advisories, details.repository, details.findings, details.rules, details.packaging, details.recovered, matchTypes}
+}
+
+func getMatchTypeString(m MatchType) string {
 	switch m {
 	case Content:
-		return gchalk.Blue("CONTENT")
+		return gchalk.Blue(m.String())
 	case ClassName:
-		return gchalk.Green("CLASS_NAME")
+		return gchalk.Green(m.String())
 	case ClassHash:
-		return gchalk.Red("CLASS_HASH")
+		return gchalk.Red(m.String())
 	case JarName:
-		return gchalk.Cyan("JAR_NAME")
+		return gchalk.Cyan(m.String())
 	case JarHash:
-		return gchalk.Yellow("JAR_HASH")
+		return gchalk.Yellow(m.String())
+	case JarAdvisory:
+		return gchalk.Magenta(m.String())
+	case ConfigLookup:
+		return gchalk.BrightRed(m.String())
+	case ContentPattern:
+		return gchalk.BrightMagenta(m.String())
+	case Spring4shell:
+		return gchalk.BrightYellow(m.String())
+	case Text4shell:
+		return gchalk.BrightCyan(m.String())
+	case Text4shellClass:
+		return gchalk.Cyan(m.String())
+	case Logback:
+		return gchalk.BrightBlue(m.String())
+	case Log4j1:
+		return gchalk.Blue(m.String())
 	}
 	return gchalk.Grey("UNKNOWN")
 }
 
+func (s *ScanResult) GetSuppressedMatches() []SuppressedMatch {
+	fileIds := make([]string, 0, len(s.suppressed))
+	for k := range s.suppressed {
+		fileIds = append(fileIds, k)
+	}
+	sort.Strings(fileIds)
+	results := make([]SuppressedMatch, len(fileIds))
+	for i, k := range fileIds {
+		results[i] = s.suppressed[k]
+	}
+	return results
+}
+
+func (s *ScanResult) GetExpiredSuppressions() []Suppression {
+	return s.expired
+}
+
 func (s *ScanResult) GetTotalScanFailures() int {
 	return len(s.failures)
 }
@@ -145,6 +329,14 @@ func (s *ScanResult) GetTotalFilesScanned() int {
 	return s.totalFilesScanned
 }
 
+func (s *ScanResult) GetTotalFilesSkipped() int {
+	return s.totalFilesSkipped
+}
+
+func (s *ScanResult) GetTotalFilesFiltered() int {
+	return s.totalFilesFiltered
+}
+
 func (s *ScanResult) GetTotalFilesMatched() int {
 	return len(s.matches)
 }
@@ -159,14 +351,48 @@ func (s *ScanResult) GetMatchCountByType(matchType MatchType) int {
 	return count
 }
 
+func (s *ScanResult) GetMatchesByType(matchType MatchType) []ScanMatch {
+	var matches []ScanMatch
+	for _, m := range s.GetMatches() {
+		if containsMatchType(m.MatchTypes(), matchType) {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
 func (s *ScanResult) GetMatchesForFileId(id string) map[MatchType]struct{} {
 	return s.matches[id]
 }
 
+func (s *ScanResult) GetSkippedMounts() []string {
+	return s.skippedMounts
+}
+
+func (s *ScanResult) AddSkippedMounts(paths ...string) {
+	s.skippedMounts = append(s.skippedMounts, paths...)
+}
+
+func (s *ScanResult) GetIgnoreFiles() []string {
+	return s.ignoreFiles
+}
+
+func (s *ScanResult) AddIgnoreFiles(paths ...string) {
+	s.ignoreFiles = append(s.ignoreFiles, paths...)
+}
+
 func (s *ScanResult) IncrementTotal() {
 	s.totalFilesScanned += 1
 }
 
+func (s *ScanResult) IncrementSkipped() {
+	s.totalFilesSkipped += 1
+}
+
+func (s *ScanResult) IncrementFiltered() {
+	s.totalFilesFiltered += 1
+}
+
 func (s *ScanResult) HasSeen(path string) bool {
 	if _, seen := s.matches[path]; seen {
 		return true
@@ -177,6 +403,8 @@ func (s *ScanResult) HasSeen(path string) bool {
 func (s *ScanResult) Merge(result ScanResult) bool {
 	hadMatches := false
 	s.totalFilesScanned += result.totalFilesScanned
+	s.totalFilesSkipped += result.totalFilesSkipped
+	s.totalFilesFiltered += result.totalFilesFiltered
 	if len(result.matches) > 0 {
 		for k, v := range result.matches {
 			if _, ok := s.matches[k]; ok {
@@ -189,6 +417,16 @@ func (s *ScanResult) Merge(result ScanResult) bool {
 		}
 		hadMatches = true
 	}
+	for k, v := range result.details {
+		s.details[k] = v
+	}
+	s.inventory = append(s.inventory, result.inventory...)
+	for k, v := range result.mitigations {
+		s.mitigations[k] = v
+	}
+	for k, v := range result.locations {
+		s.locations[k] = v
+	}
 	if len(result.failures) > 0 {
 		for k, v := range result.failures {
 			if _, ok := s.failures[k]; ok {
@@ -214,6 +452,44 @@ func (s *ScanResult) AddMatch(id string, types ...MatchType) {
 	}
 }
 
+func (s *ScanResult) AddMatchDetails(id string, path []string, digests Digests, hashEntry *HashDatabaseEntry) {
+	details := s.details[id]
+	details.path = path
+	details.digests = digests
+	details.hashEntry = hashEntry
+	s.details[id] = details
+}
+
+// AddFinding records the match types, details, components and mitigations a detector found for the
+// file.
+func (s *ScanResult) AddFinding(id string, path []string, finding Finding) {
+	s.AddComponents(id, path, finding.Components...)
+	s.AddMitigations(id, path, finding.Mitigations...)
+	if !finding.IsMatch() {
+		return
+	}
+	s.AddMatch(id, finding.MatchTypes...)
+	details := s.details[id]
+	details.path = path
+	if finding.HashEntry != nil {
+		details.hashEntry = finding.HashEntry
+	}
+	details.advisories = MergeAdvisories(details.advisories, finding.Advisories)
+	details.findings = append(details.findings, finding.Findings...)
+	details.rules = append(details.rules, finding.Rules...)
+	s.details[id] = details
+}
+
+// markRecovered flags every match as found within a corrupted zip whose entries were recovered.
+func (s *ScanResult) markRecovered() {
+	for id := range s.matches {
+		if details, ok := s.details[id]; ok {
+			details.recovered = true
+			s.details[id] = details
+		}
+	}
+}
+
 func (s *ScanResult) AddFailure(id string, err error) {
 	m, ok := s.failures[id]
 	if !ok {
@@ -224,102 +500,155 @@ func (s *ScanResult) AddFailure(id string, err error) {
 }
 
 type scanner struct {
-	classScanner ClassScanner
-	jarScanner   JarScanner
-	globMatcher  GlobMatcher
-	console      Console
+	detectors   []Detector
+	globMatcher GlobMatcher
+	walkOptions WalkOptions
+	fileFilter  FileFilter
+	console     Console
 }
 
-func NewScanner(classScanner ClassScanner, jarScanner JarScanner, globMatcher GlobMatcher, verbosity int) Scanner {
+func NewScanner(detectors []Detector, globMatcher GlobMatcher, walkOptions WalkOptions, fileFilter FileFilter, verbosity int) Scanner {
 	return &scanner{
-		classScanner: classScanner,
-		jarScanner:   jarScanner,
-		globMatcher:  globMatcher,
-		console:      NewConsole(verbosity),
+		detectors:   detectors,
+		globMatcher: globMatcher,
+		walkOptions: walkOptions,
+		fileFilter:  fileFilter,
+		console:     NewConsole(verbosity),
 	}
 }
 
 func (s *scanner) Scan(roots ...string) (ScanResult, error) {
 	result := NewScanResult()
-	walker := NewWalker(s.globMatcher)
-	err := walker.WalkDirs(func(fileId string, filePath string, progress Progress) error {
+	walker, err := NewWalker(s.globMatcher, s.walkOptions)
+	if err != nil {
+		return result, err
+	}
+	err = walker.WalkDirs(s.scanFunc(&result), roots...)
+	result.AddSkippedMounts(walker.GetSkippedMounts()...)
+	result.AddIgnoreFiles(walker.GetIgnoreFiles()...)
+	return result, err
+}
+
+func (s *scanner) ScanPaths(paths ...string) (ScanResult, error) {
+	result := NewScanResult()
+	walker, err := NewWalker(s.globMatcher, s.walkOptions)
+	if err != nil {
+		return result, err
+	}
+	err = walker.WalkPaths(s.scanFunc(&result), paths...)
+	result.AddSkippedMounts(walker.GetSkippedMounts()...)
+	result.AddIgnoreFiles(walker.GetIgnoreFiles()...)
+	return result, err
+}
+
+func (s *scanner) scanFunc(result *ScanResult) WalkDirFunc {
+	return func(fileId string, filePath string, fileType fs.FileMode, progress Progress) error {
 		if result.HasSeen(filePath) {
 			return nil
 		}
-		scanResult, err := s.scan(fileId, filePath, progress)
+		if !fileType.IsRegular() {
+			// Devices, pipes and sockets may block, or never end, when read.
+			result.IncrementTotal()
+			result.IncrementSkipped()
+			s.console.Skipped(progress, fileId)
+			return nil
+		}
+		if s.fileFilter.IsFiltered(filePath, s.isConfigFile(fileId, filePath)) {
+			result.IncrementFiltered()
+			s.console.Filtered(progress, fileId)
+			return nil
+		}
+		scanResult, err := s.scan(fileId, []string{fileId}, filePath, nil, progress)
 		if err != nil {
 			result.AddFailure(fileId, fmt.Errorf("failed to scan: %v", err))
 		}
+		if len(scanResult.matches) > 0 || len(scanResult.inventory) > 0 {
+			scanResult.AddLocation(fileId, filePath)
+		}
 		result.Merge(scanResult)
 		return nil
-	}, roots...)
-	return result, err
+	}
+}
+
+// isConfigFile reports whether the config detector is enabled and accepts the file.
+func (s *scanner) isConfigFile(fileId string, filePath string) bool {
+	for _, detector := range s.detectors {
+		if detector.Name() == ConfigDetectorName {
+			return detector.Accepts(newFileEntry(fileId, filePath))
+		}
+	}
+	return false
 }
 
-func (s *scanner) scan(id string, source interface{}, progress Progress) (ScanResult, error) {
-	var err error
-	var reader ContentReader
+// scan scans the file, or the entry of the archive, and any archive it contains.
+func (s *scanner) scan(id string, path []string, source interface{}, archive *packagedArchive, progress Progress) (ScanResult, error) {
+	var entry scanEntry
+	var packaging *Packaging
 	result := NewScanResult()
 	fileId := id
-	if contentFile, ok := source.(ContentFile); ok {
-		defer func(contentFile ContentFile) {
-			_ = contentFile.Close()
-		}(contentFile)
+	if archiveEntry, ok := source.(*contentFileEntry); ok {
+		defer func(archiveEntry *contentFileEntry) {
+			_ = archiveEntry.Close()
+		}(archiveEntry)
 
-		if contentFile.IsDir() {
-			return result, nil
+		result.IncrementTotal()
+		fileId = fmt.Sprintf("%s @ %s", fileId, archiveEntry.Name())
+		path = append(path[:len(path):len(path)], archiveEntry.Name())
+		entry = archiveEntry
+		if archive != nil {
+			packaging = archive.entryPackaging(archiveEntry.Name())
 		}
+	} else if filename, ok := source.(string); ok {
 		result.IncrementTotal()
-		fileId = fmt.Sprintf("%s @ %s", fileId, contentFile.Name())
-		if strings.HasSuffix(contentFile.Name(), ".class") {
-			matchTypes, err := s.classScanner.Scan(contentFile)
-			if err != nil {
-				result.AddFailure(fileId, fmt.Errorf("failed to scan class: %v", err))
-				s.console.Error(progress, fileId)
-				return result, nil
-			}
-			if len(matchTypes) == 0 {
-				s.console.NotMatched(progress, fileId)
-				return result, nil
-			}
-			result.AddMatch(fileId, matchTypes...)
+		entry = newFileEntry(fileId, filename)
+	} else {
+		return result, nil
+	}
+	inspected, err := s.inspectFile(&result, fileId, path, entry)
+	if err != nil {
+		result.AddFailure(fileId, err)
+		s.console.Error(progress, fileId)
+		return result, nil
+	}
+	reader, err := entry.open(s.globMatcher)
+	if err != nil {
+		result.AddFailure(fileId, err)
+		s.console.Error(progress, fileId)
+		return result, nil
+	}
+	if reader == nil {
+		if _, matched := result.matches[fileId]; matched {
+			details := result.details[fileId]
+			details.digests, _ = entry.Hashes()
+			details.packaging = packaging
+			result.details[fileId] = details
 			s.console.Matched(progress, fileId)
-			return result, nil
+		} else if inspected {
+			s.console.NotMatched(progress, fileId)
 		} else {
-			contentFileReader := contentFile.Reader()
-			contentReader, err := GetContentReader(contentFileReader, s.globMatcher)
-			if err != nil {
-				result.AddFailure(fileId, err)
-				s.console.Error(progress, fileId)
-				return result, nil
-			}
-			if contentReader == nil {
-				s.console.Skipped(progress, fileId)
-				return result, nil
-			}
-			reader = contentReader
+			result.IncrementSkipped()
+			s.console.Skipped(progress, fileId)
 		}
-	} else if filename, ok := source.(string); ok {
-		result.IncrementTotal()
-		contentReader, err := GetContentReaderFromFile(filename, s.globMatcher)
+		return result, nil
+	}
+	defer func(reader ContentReader) {
+		_ = reader.Close()
+	}(reader)
+	var repository *Repository
+	for _, detector := range s.detectors {
+		finding, err := detector.InspectArchive(reader)
 		if err != nil {
 			result.AddFailure(fileId, err)
 			s.console.Error(progress, fileId)
 			return result, nil
 		}
-		if contentReader == nil {
-			s.console.Skipped(progress, fileId)
-			return result, nil
+		if finding.Repository != nil {
+			repository = finding.Repository
 		}
-		reader = contentReader
-	}
-	matchTypes, err := s.jarScanner.Scan(reader)
-	if err != nil {
-		result.AddFailure(fileId, err)
-		s.console.Error(progress, fileId)
-		return result, nil
+		result.AddFinding(fileId, path, finding)
 	}
-	result.AddMatch(fileId, matchTypes...)
+	recovered := IsRecovered(reader)
+	packaged := newPackagedArchive(reader, packaging)
 	files := reader.Files()
 	for {
 		next, err := files.Next()
@@ -331,17 +660,54 @@ func (s *scanner) scan(id string, source interface{}, progress Progress) (ScanRe
 		if next == nil {
 			break
 		}
-		contentScanResult, err := s.scan(fileId, next, progress)
+		if failure, ok := next.(FileFailure); ok {
+			result.AddFailure(fmt.Sprintf("%s @ %s", fileId, failure.Name), failure.Err)
+			s.console.Error(progress, fileId)
+			continue
+		}
+		contentFile, ok := next.(ContentFile)
+		if !ok {
+			continue
+		}
+		if contentFile.IsDir() {
+			_ = contentFile.Close()
+			continue
+		}
+		archiveEntry := newContentFileEntry(reader.Filename(), contentFile)
+		for _, detector := range s.detectors {
+			finding, err := detector.InspectArchiveEntry(reader, archiveEntry)
+			if err != nil {
+				result.AddFailure(fileId, fmt.Errorf("failed to apply %s detector to %s: %v", detector.Name(), archiveEntry.Name(), err))
+				continue
+			}
+			result.AddFinding(fileId, path, finding)
+		}
+		contentScanResult, err := s.scan(fileId, path, archiveEntry, packaged, progress)
 		if err != nil {
 			result.AddFailure(fileId, fmt.Errorf("failed to scan: %v", err))
 			s.console.Error(progress, fileId)
 		}
+		if recovered {
+			contentScanResult.markRecovered()
+		}
 		if result.Merge(contentScanResult) {
 			result.AddMatch(fileId, Content)
+			if _, ok := result.details[fileId]; !ok {
+				result.AddMatchDetails(fileId, path, nil, nil)
+			}
 		}
 	}
 	currentMatches := result.GetMatchesForFileId(fileId)
 	_, contentMatch := currentMatches[Content]
+	if details, ok := result.details[fileId]; ok {
+		if details.digests == nil && (len(currentMatches) > 1 || !contentMatch) {
+			details.digests, _ = reader.Hashes()
+		}
+		details.repository = repository
+		details.packaging = packaging
+		details.recovered = details.recovered || recovered
+		result.details[fileId] = details
+	}
 	if len(currentMatches) > 1 || (len(currentMatches) > 0 && !contentMatch) {
 		s.console.Matched(progress, fileId)
 	} else {
@@ -349,3 +715,20 @@ func (s *scanner) scan(id string, source interface{}, progress Progress) (ScanRe
 	}
 	return result, nil
 }
+
+// inspectFile applies the detectors accepting the file, reporting whether any did.
+func (s *scanner) inspectFile(result *ScanResult, fileId string, path []string, entry Entry) (bool, error) {
+	inspected := false
+	for _, detector := range s.detectors {
+		if !detector.Accepts(entry) {
+			continue
+		}
+		inspected = true
+		finding, err := detector.InspectFile(entry)
+		if err != nil {
+			return inspected, fmt.Errorf("failed to apply %s detector: %v", detector.Name(), err)
+		}
+		result.AddFinding(fileId, path, finding)
+	}
+	return inspected, nil
+}
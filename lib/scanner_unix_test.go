@@ -0,0 +1,102 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package lib
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestScanClosesArchives scans more jars than the process may have open files, which fails unless
+// each archive is closed once scanned.
+func TestScanClosesArchives(t *testing.T) {
+	const jars = 300
+	root := t.TempDir()
+	for i := 0; i < jars; i++ {
+		writeJar(t, filepath.Join(root, fmt.Sprintf("library-%d-1.0.jar", i)))
+	}
+
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		t.Fatal(err)
+	}
+	lowered := limit
+	lowered.Cur = 128
+	if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &lowered); err != nil {
+		t.Skipf("unable to lower the open file limit: %v", err)
+	}
+	defer func() {
+		_ = syscall.Setrlimit(syscall.RLIMIT_NOFILE, &limit)
+	}()
+
+	globMatcher, err := NewGlobMatcher([]string{"**/**"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashMatcher, err := NewHashMatcherFromString("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	detectors, err := NewDetectors([]string{ClassDetectorName, JarDetectorName}, DetectorOptions{
+		ClassNameMatcher: NewClassNameMatcher(nil),
+		ClassHashMatcher: hashMatcher,
+		JarNameMatcher:   NewJarNameMatcher(),
+		JarHashMatcher:   hashMatcher,
+		AdvisoryMatcher:  NewAdvisoryMatcher(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewScanner(detectors, globMatcher, WalkOptions{MaxDepth: -1}, NewFileFilter(0, 0, time.Time{}, time.Time{}, false), 0).Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failures := result.GetFailures(); len(failures) > 0 {
+		t.Fatalf("expected no failures, got %d, first: %s", len(failures), failures[0])
+	}
+	if scanned := result.GetTotalFilesScanned(); scanned < jars {
+		t.Fatalf("expected at least %d files scanned, got %d", jars, scanned)
+	}
+}
+
+func writeJar(t *testing.T, filename string) {
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(f)
+	w := zip.NewWriter(f)
+	entry, err := w.Create("META-INF/MANIFEST.MF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := entry.Write([]byte("Manifest-Version: 1.0\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
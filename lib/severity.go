@@ -0,0 +1,42 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+type Severity int
+
+const (
+	Info Severity = iota
+	Low
+	Medium
+	High
+	Critical
+)
+
+// Severity returns how strongly a match of this type indicates an exploitable log4shell.  Hash
+// matches identify known vulnerable artifacts, while name matches may be unrelated code.
+func (i MatchType) Severity() Severity {
+	switch i {
+	case JarHash:
+		return Critical
+	case ClassHash, JarAdvisory, ContentPattern, Spring4shell, Text4shell:
+		return High
+	case JarName, ConfigLookup, Logback, Log4j1:
+		return Medium
+	case ClassName, Text4shellClass:
+		return Low
+	}
+	return Info
+}
@@ -0,0 +1,93 @@
+// Code generated by "enumer -type Severity -transform lower lib/severity.go"; DO NOT EDIT.
+
+package lib
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _SeverityName = "infolowmediumhighcritical"
+
+var _SeverityIndex = [...]uint8{0, 4, 7, 13, 17, 25}
+
+const _SeverityLowerName = "infolowmediumhighcritical"
+
+func (i Severity) String() string {
+	if i < 0 || i >= Severity(len(_SeverityIndex)-1) {
+		return fmt.Sprintf("Severity(%d)", i)
+	}
+	return _SeverityName[_SeverityIndex[i]:_SeverityIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _SeverityNoOp() {
+	var x [1]struct{}
+	_ = x[Info-(0)]
+	_ = x[Low-(1)]
+	_ = x[Medium-(2)]
+	_ = x[High-(3)]
+	_ = x[Critical-(4)]
+}
+
+var _SeverityValues = []Severity{Info, Low, Medium, High, Critical}
+
+var _SeverityNameToValueMap = map[string]Severity{
+	_SeverityName[0:4]:   Info,
+	_SeverityName[4:7]:   Low,
+	_SeverityName[7:13]:  Medium,
+	_SeverityName[13:17]: High,
+	_SeverityName[17:25]: Critical,
+}
+
+var _SeverityLowerNameToValueMap = map[string]Severity{
+	_SeverityLowerName[0:4]:   Info,
+	_SeverityLowerName[4:7]:   Low,
+	_SeverityLowerName[7:13]:  Medium,
+	_SeverityLowerName[13:17]: High,
+	_SeverityLowerName[17:25]: Critical,
+}
+
+var _SeverityNames = []string{
+	_SeverityName[0:4],
+	_SeverityName[4:7],
+	_SeverityName[7:13],
+	_SeverityName[13:17],
+	_SeverityName[17:25],
+}
+
+// SeverityString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func SeverityString(s string) (Severity, error) {
+	if val, ok := _SeverityNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _SeverityLowerNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Severity values", s)
+}
+
+// SeverityValues returns all values of the enum
+func SeverityValues() []Severity {
+	return _SeverityValues
+}
+
+// SeverityStrings returns a slice of all String values of the enum
+func SeverityStrings() []string {
+	strs := make([]string, len(_SeverityNames))
+	copy(strs, _SeverityNames)
+	return strs
+}
+
+// IsASeverity returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Severity) IsASeverity() bool {
+	for _, v := range _SeverityValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
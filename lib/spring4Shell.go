@@ -0,0 +1,113 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const springIntrospectionClass = "org/springframework/beans/CachedIntrospectionResults.class"
+
+var spring4ShellAdvisory = Advisory{
+	Id:      "CVE-2022-22965",
+	Aliases: []string{"GHSA-36p3-wjmg-h94x"},
+	Summary: "Spring Framework RCE via data binding on JDK 9+ (Spring4Shell)",
+}
+
+// spring4ShellDetector identifies affected spring-beans and spring-webmvc jars by their file name, or
+// manifest, and notes whether they are packaged in a war, as exploitation requires a war deployed to
+// Tomcat running on JDK 9+.
+type spring4ShellDetector struct {
+	artifactDetector
+	artifacts             []vulnerableArtifact
+	fixedClassHashMatcher HashMatcher
+}
+
+// NewSpring4ShellDetector creates the spring4shell detector.  CachedIntrospectionResults classes
+// matching fixedClassHashMatcher, such as those of spring-beans 5.3.18 or 5.2.20, are reported as
+// mitigations.
+func NewSpring4ShellDetector(fixedClassHashMatcher HashMatcher) (Detector, error) {
+	var artifacts []vulnerableArtifact
+	for _, artifactId := range []string{"spring-beans", "spring-webmvc"} {
+		artifact, err := newVulnerableArtifact("org.springframework", artifactId, spring4ShellAdvisory, "5.3.0/5.3.17", "5.2.0/5.2.19")
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	if fixedClassHashMatcher == nil {
+		var err error
+		if fixedClassHashMatcher, err = NewHashMatcherFromString(""); err != nil {
+			return nil, err
+		}
+	}
+	d := spring4ShellDetector{
+		artifacts:             artifacts,
+		fixedClassHashMatcher: fixedClassHashMatcher,
+	}
+	d.artifactDetector = artifactDetector{match: d.match}
+	return d, nil
+}
+
+func (d spring4ShellDetector) Name() string {
+	return Spring4ShellDetectorName
+}
+
+func (d spring4ShellDetector) Accepts(entry Entry) bool {
+	return len(entry.Archive()) > 0 && strings.HasSuffix(entry.Name(), springIntrospectionClass)
+}
+
+func (d spring4ShellDetector) InspectFile(entry Entry) (Finding, error) {
+	digests, err := entry.Hashes()
+	if err != nil {
+		return Finding{}, err
+	}
+	hashEntry, ok := d.fixedClassHashMatcher.GetHashMatch(digests)
+	if !ok {
+		return Finding{}, nil
+	}
+	return Finding{
+		Mitigations: []MitigationSetting{{
+			Description: fmt.Sprintf("%s is fixed for %s [%s]", filepath.Base(springIntrospectionClass), spring4ShellAdvisory.Id, hashEntry.Description()),
+			Enabled:     true,
+		}},
+	}, nil
+}
+
+func (d spring4ShellDetector) match(filename string, coordinates Coordinates) Finding {
+	advisories := matchVulnerableArtifacts(d.artifacts, coordinates)
+	if len(advisories) == 0 {
+		return Finding{}
+	}
+	return Finding{
+		MatchTypes: []MatchType{Spring4shell},
+		Advisories: advisories,
+		Findings:   []string{fmt.Sprintf("%s %s %s", coordinates.ArtifactId, coordinates.Version, springPackaging(filename))},
+	}
+}
+
+// springPackaging describes how the packaging of the jar affects exploitability.
+func springPackaging(filename string) string {
+	filename = "/" + strings.ReplaceAll(filename, "\\", "/")
+	if strings.Contains(filename, "/WEB-INF/lib/") {
+		return "packaged in a war, exploitable when deployed to Tomcat on JDK 9+"
+	} else if strings.Contains(filename, "/BOOT-INF/lib/") {
+		return "packaged in a Spring Boot jar, not known to be exploitable"
+	}
+	return "not packaged in a war, exploitable only when deployed as a war to Tomcat on JDK 9+"
+}
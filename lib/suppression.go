@@ -0,0 +1,213 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	suppressionDateFormat = "2006-01-02"
+	pathSeparator         = "!/"
+)
+
+// Suppression accepts the risk of matches whose path matches the Path glob and/or whose hash equals
+// Hash until the end of the day on which it Expires.
+type Suppression struct {
+	Path          string `yaml:"path,omitempty"`
+	Hash          string `yaml:"hash,omitempty"`
+	Justification string `yaml:"justification"`
+	Owner         string `yaml:"owner"`
+	Expires       string `yaml:"expires"`
+	expires       time.Time
+}
+
+func NewSuppression(path string, hash string, justification string, owner string, expires time.Time) Suppression {
+	return Suppression{
+		Path:          path,
+		Hash:          hash,
+		Justification: justification,
+		Owner:         owner,
+		Expires:       expires.Format(suppressionDateFormat),
+		expires:       expires,
+	}
+}
+
+func (s Suppression) String() string {
+	var keys []string
+	if len(s.Path) > 0 {
+		keys = append(keys, fmt.Sprintf("path=%s", s.Path))
+	}
+	if len(s.Hash) > 0 {
+		keys = append(keys, fmt.Sprintf("hash=%s", s.Hash))
+	}
+	return fmt.Sprintf("%s (owner: %s, expires: %s): %s", strings.Join(keys, " "), s.Owner, s.Expires, s.Justification)
+}
+
+func (s Suppression) IsExpired(now time.Time) bool {
+	return !now.Before(s.expires.AddDate(0, 0, 1))
+}
+
+func (s Suppression) IsMatch(match ScanMatch) bool {
+	if len(s.Hash) > 0 {
+		algorithm, hash, err := ParseHash(s.Hash)
+		if err != nil || hash != match.Digests()[algorithm] {
+			return false
+		}
+	}
+	if len(s.Path) > 0 {
+		if ok, _ := doublestar.Match(s.Path, JoinPath(match.Path())); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Suppression) validate() error {
+	if len(s.Path) == 0 && len(s.Hash) == 0 {
+		return fmt.Errorf("path or hash is required")
+	}
+	if len(s.Path) > 0 && !doublestar.ValidatePattern(s.Path) {
+		return fmt.Errorf("invalid path glob: %s", s.Path)
+	}
+	if len(s.Hash) > 0 {
+		algorithm, _, err := ParseHash(s.Hash)
+		if err != nil {
+			return err
+		}
+		RequireHashAlgorithm(algorithm)
+	}
+	if len(strings.TrimSpace(s.Justification)) == 0 {
+		return fmt.Errorf("justification is required")
+	}
+	if len(strings.TrimSpace(s.Owner)) == 0 {
+		return fmt.Errorf("owner is required")
+	}
+	expires, err := time.ParseInLocation(suppressionDateFormat, s.Expires, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid expires, expected %s: %s", suppressionDateFormat, s.Expires)
+	}
+	s.expires = expires
+	return nil
+}
+
+type SuppressedMatch struct {
+	ScanMatch
+	Suppression Suppression
+}
+
+func (s SuppressedMatch) String() string {
+	return fmt.Sprintf("%s\n        %s", s.ScanMatch, s.Suppression)
+}
+
+type Suppressions struct {
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+func LoadSuppressions(file string) (Suppressions, error) {
+	suppressions := Suppressions{}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return suppressions, err
+	}
+	if err := yaml.Unmarshal(content, &suppressions); err != nil {
+		return suppressions, err
+	}
+	for i := range suppressions.Suppressions {
+		if err := suppressions.Suppressions[i].validate(); err != nil {
+			return suppressions, fmt.Errorf("invalid suppression %d: %v", i+1, err)
+		}
+	}
+	return suppressions, nil
+}
+
+func (s Suppressions) Write(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(s); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// JoinPath joins the segments of a structured match path in the form used by java jar urls, ie:
+// app.war!/WEB-INF/lib/log4j-core-2.14.1.jar!/org/apache/logging/log4j/core/lookup/JndiLookup.class
+func JoinPath(path []string) string {
+	return strings.Join(path, pathSeparator)
+}
+
+// EscapeGlob escapes the glob meta characters in path so that it only matches itself.
+func EscapeGlob(path string) string {
+	var sb strings.Builder
+	for _, r := range path {
+		switch r {
+		case '*', '?', '[', ']', '{', '}', '\\':
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// ApplySuppressions moves matches covered by an unexpired suppression out of the result.  Expired
+// suppressions are recorded but suppress nothing.
+func (s *ScanResult) ApplySuppressions(suppressions Suppressions, now time.Time) {
+	var active []Suppression
+	for _, suppression := range suppressions.Suppressions {
+		if suppression.IsExpired(now) {
+			s.expired = append(s.expired, suppression)
+		} else {
+			active = append(active, suppression)
+		}
+	}
+	if len(active) == 0 {
+		return
+	}
+	for _, match := range s.GetMatches() {
+		for _, suppression := range active {
+			if suppression.IsMatch(match) {
+				s.suppressed[match.FileId()] = SuppressedMatch{match, suppression}
+				delete(s.matches, match.FileId())
+				break
+			}
+		}
+	}
+	for fileId, matchTypes := range s.matches {
+		if _, content := matchTypes[Content]; !content || s.hasNestedMatch(fileId) {
+			continue
+		}
+		delete(matchTypes, Content)
+		if len(matchTypes) == 0 {
+			delete(s.matches, fileId)
+		}
+	}
+}
+
+func (s *ScanResult) hasNestedMatch(fileId string) bool {
+	prefix := fileId + " @ "
+	for k, matchTypes := range s.matches {
+		if _, content := matchTypes[Content]; strings.HasPrefix(k, prefix) && (len(matchTypes) > 1 || !content) {
+			return true
+		}
+	}
+	return false
+}
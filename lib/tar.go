@@ -85,6 +85,10 @@ func (r *tarReader) Hash() (string, error) {
 	return r.contentFileReader.Hash()
 }
 
+func (r *tarReader) Hashes() (Digests, error) {
+	return r.contentFileReader.Hashes()
+}
+
 func (r *tarReader) Close() error {
 	return r.contentFileReader.Close()
 }
@@ -0,0 +1,119 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+var (
+	// text4ShellClasses are the commons-text classes implementing the script, dns and url lookups.
+	text4ShellClasses  = []string{"StringLookupFactory", "ScriptStringLookup"}
+	text4ShellAdvisory = Advisory{
+		Id:      "CVE-2022-42889",
+		Aliases: []string{"GHSA-599f-7c49-w659"},
+		Summary: "Apache Commons Text RCE via StringSubstitutor script, dns and url lookups (Text4Shell)",
+	}
+	configurationInterpolationAdvisory = Advisory{
+		Id:      "CVE-2022-33980",
+		Aliases: []string{"GHSA-xj57-8qj4-c4m6"},
+		Summary: "Apache Commons Configuration RCE via variable interpolation script, dns and url lookups",
+	}
+)
+
+// text4ShellDetector identifies affected commons-text and commons-configuration2 jars by their file
+// name, or manifest, and the commons-text lookup classes by hash, or by name outside of commons-text
+// where they have likely been shaded.
+type text4ShellDetector struct {
+	artifactDetector
+	artifacts        []vulnerableArtifact
+	classNameMatcher ClassNameMatcher
+	classHashMatcher HashMatcher
+}
+
+// NewText4ShellDetector creates the text4shell detector.  classHashMatcher matches the
+// StringLookupFactory and ScriptStringLookup classes of affected commons-text versions.
+func NewText4ShellDetector(classHashMatcher HashMatcher) (Detector, error) {
+	text, err := newVulnerableArtifact("org.apache.commons", "commons-text", text4ShellAdvisory, "1.5/1.9")
+	if err != nil {
+		return nil, err
+	}
+	configuration, err := newVulnerableArtifact("org.apache.commons", "commons-configuration2", configurationInterpolationAdvisory, "2.4/2.7")
+	if err != nil {
+		return nil, err
+	}
+	if classHashMatcher == nil {
+		if classHashMatcher, err = NewHashMatcherFromString(""); err != nil {
+			return nil, err
+		}
+	}
+	d := text4ShellDetector{
+		artifacts:        []vulnerableArtifact{text, configuration},
+		classNameMatcher: NewClassNameMatcher(text4ShellClasses),
+		classHashMatcher: classHashMatcher,
+	}
+	d.artifactDetector = artifactDetector{match: d.match}
+	return d, nil
+}
+
+func (d text4ShellDetector) Name() string {
+	return Text4ShellDetectorName
+}
+
+func (d text4ShellDetector) Accepts(entry Entry) bool {
+	if len(entry.Archive()) == 0 {
+		return false
+	}
+	match, _ := d.classNameMatcher.IsMatch(filepath.Base(entry.Name()))
+	return match
+}
+
+func (d text4ShellDetector) InspectFile(entry Entry) (Finding, error) {
+	basename := filepath.Base(entry.Name())
+	digests, err := entry.Hashes()
+	if err != nil {
+		return Finding{}, err
+	}
+	if hashEntry, ok := d.classHashMatcher.GetHashMatch(digests); ok {
+		return Finding{
+			MatchTypes: []MatchType{Text4shell},
+			HashEntry:  &hashEntry,
+			Advisories: []Advisory{d.artifacts[0].advisory},
+			Findings:   []string{fmt.Sprintf("%s from an affected commons-text", basename)},
+		}, nil
+	}
+	switch CoordinatesFromFilename(filepath.Base(entry.Archive())).ArtifactId {
+	case "commons-text", "commons-configuration2":
+		return Finding{}, nil
+	}
+	return Finding{
+		MatchTypes: []MatchType{Text4shellClass},
+		Findings:   []string{fmt.Sprintf("%s outside of commons-text, possibly shaded", basename)},
+	}, nil
+}
+
+func (d text4ShellDetector) match(_ string, coordinates Coordinates) Finding {
+	advisories := matchVulnerableArtifacts(d.artifacts, coordinates)
+	if len(advisories) == 0 {
+		return Finding{}
+	}
+	return Finding{
+		MatchTypes: []MatchType{Text4shell},
+		Advisories: advisories,
+		Findings:   []string{fmt.Sprintf("%s %s", coordinates.ArtifactId, coordinates.Version)},
+	}
+}
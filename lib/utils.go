@@ -16,10 +16,33 @@
 package lib
 
 import (
+	"fmt"
 	"github.com/mitchellh/go-homedir"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
+var sizeSuffixes = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"KIB", 1 << 10},
+	{"MIB", 1 << 20},
+	{"GIB", 1 << 30},
+	{"TIB", 1 << 40},
+	{"KB", 1 << 10},
+	{"MB", 1 << 20},
+	{"GB", 1 << 30},
+	{"TB", 1 << 40},
+	{"K", 1 << 10},
+	{"M", 1 << 20},
+	{"G", 1 << 30},
+	{"T", 1 << 40},
+	{"B", 1},
+}
+
 func AbsolutePath(path string) (string, error) {
 	expandedPath, err := homedir.Expand(path)
 	if err != nil {
@@ -31,3 +54,48 @@ func AbsolutePath(path string) (string, error) {
 	}
 	return absPath, nil
 }
+
+// ParseSize parses a size such as 512, 100K, 10MB or 2GiB into a number of bytes.  An empty size is 0.
+func ParseSize(size string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(size))
+	if len(value) == 0 {
+		return 0, nil
+	}
+	multiplier := int64(1)
+	for _, s := range sizeSuffixes {
+		if strings.HasSuffix(value, s.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, s.suffix))
+			multiplier = s.multiplier
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size: %s", size)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// ParseTime parses an RFC3339 timestamp, a date in the form 2006-01-02, or a duration such as 72h
+// or 30d which is interpreted relative to now.  An empty time is the zero time.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s", value)
+}
@@ -0,0 +1,106 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// vulnerableArtifact is an artifact, and inclusive version ranges, affected by an advisory.
+type vulnerableArtifact struct {
+	matcher  JarNameMatcher
+	advisory Advisory
+}
+
+// newVulnerableArtifact creates a vulnerableArtifact from ranges of the form min/max.
+func newVulnerableArtifact(groupId string, artifactId string, advisory Advisory, ranges ...string) (vulnerableArtifact, error) {
+	matcher := NewJarNameMatcher()
+	for _, r := range ranges {
+		if err := matcher.AddMatchers(artifactId + "/" + r); err != nil {
+			return vulnerableArtifact{}, err
+		}
+	}
+	advisory.Package = Coordinates{GroupId: groupId, ArtifactId: artifactId}
+	return vulnerableArtifact{matcher: matcher, advisory: advisory}, nil
+}
+
+// matchVulnerableArtifacts returns the advisories affecting the artifact.
+func matchVulnerableArtifacts(artifacts []vulnerableArtifact, coordinates Coordinates) []Advisory {
+	var advisories []Advisory
+	for _, a := range artifacts {
+		if a.matcher.IsCoordinatesMatch(coordinates) {
+			advisories = append(advisories, a.advisory)
+		}
+	}
+	return advisories
+}
+
+// artifactDetector implements InspectArchive and InspectArchiveEntry for detectors which identify
+// artifacts by the file name of a jar or, when that lacks a version, such as commons-text.jar, by its
+// manifest.  match returns the finding for the coordinates of the jar with the given file name.
+type artifactDetector struct {
+	BaseDetector
+	match func(filename string, coordinates Coordinates) Finding
+}
+
+func (d artifactDetector) InspectArchive(contentReader ContentReader) (Finding, error) {
+	if coordinates, ok := jarCoordinates(contentReader); ok && len(coordinates.Version) > 0 {
+		return d.match(contentReader.Filename(), coordinates), nil
+	}
+	return Finding{}, nil
+}
+
+func (d artifactDetector) InspectArchiveEntry(contentReader ContentReader, entry Entry) (Finding, error) {
+	if coordinates, ok := manifestCoordinates(contentReader, entry); ok && len(coordinates.Version) > 0 {
+		return d.match(contentReader.Filename(), coordinates), nil
+	}
+	return Finding{}, nil
+}
+
+// jarCoordinates returns the coordinates of a jar derived from its file name.
+func jarCoordinates(contentReader ContentReader) (Coordinates, bool) {
+	if !strings.HasSuffix(contentReader.Filename(), ".jar") {
+		return Coordinates{}, false
+	}
+	return CoordinatesFromFilename(filepath.Base(contentReader.Filename())), true
+}
+
+// manifestCoordinates returns the coordinates of a jar whose file name lacks a version, such as
+// commons-text.jar, from its manifest entry.
+func manifestCoordinates(contentReader ContentReader, entry Entry) (Coordinates, bool) {
+	if !IsManifest(entry.Name()) {
+		return Coordinates{}, false
+	}
+	coordinates, ok := jarCoordinates(contentReader)
+	if !ok || len(coordinates.Version) > 0 {
+		return Coordinates{}, false
+	}
+	content, err := entry.Content()
+	if err != nil {
+		return Coordinates{}, false
+	}
+	manifest, err := ParseManifest(bytes.NewReader(content))
+	if err != nil {
+		return Coordinates{}, false
+	}
+	if len(manifest.ArtifactId) > 0 {
+		coordinates.ArtifactId = manifest.ArtifactId
+	}
+	coordinates.Version = manifest.Version
+	return coordinates, true
+}
@@ -22,26 +22,73 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 )
 
-type WalkDirFunc func(fileId string, filePath string, p Progress) error
+// WalkDirFunc is called with each file found.  fileType holds the type bits of the file, or of the
+// target of a symlink, as read from the directory without a stat.
+type WalkDirFunc func(fileId string, filePath string, fileType fs.FileMode, p Progress) error
 
 type walkDirFunc func(root string, path string, d DirEntryEx, p Progress, err error) error
 
 type Walker interface {
 	WalkDirs(fn WalkDirFunc, roots ...string) error
+	WalkPaths(fn WalkDirFunc, paths ...string) error
+	GetSkippedMounts() []string
+	GetIgnoreFiles() []string
+}
+
+type WalkOptions struct {
+	OneFileSystem bool
+	SkipFsTypes   []string
+	MaxDepth      int
+	IgnoreFiles   bool
 }
 
 type walker struct {
-	globMatcher GlobMatcher
-	seenPaths   map[string]struct{}
+	globMatcher   GlobMatcher
+	options       WalkOptions
+	skipFsTypes   map[string]struct{}
+	mounts        map[string]string
+	rootDevice    *uint64
+	skippedMounts []string
+	ignores       []IgnoreMatcher
+	ignoreFiles   []string
+	ignoreDirs    map[string]IgnoreMatcher
+	seenPaths     map[string]struct{}
 }
 
-func NewWalker(globMatcher GlobMatcher) Walker {
-	return &walker{
-		globMatcher: globMatcher,
-		seenPaths:   map[string]struct{}{},
+func NewWalker(globMatcher GlobMatcher, options WalkOptions) (Walker, error) {
+	skipFsTypes := map[string]struct{}{}
+	for _, fsType := range options.SkipFsTypes {
+		skipFsTypes[fsType] = struct{}{}
+	}
+	var mounts map[string]string
+	if len(skipFsTypes) > 0 {
+		var err error
+		mounts, err = LoadMounts()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load mounts: %v", err)
+		}
 	}
+	return &walker{
+		globMatcher:   globMatcher,
+		options:       options,
+		skipFsTypes:   skipFsTypes,
+		mounts:        mounts,
+		skippedMounts: []string{},
+		ignoreFiles:   []string{},
+		ignoreDirs:    map[string]IgnoreMatcher{},
+		seenPaths:     map[string]struct{}{},
+	}, nil
+}
+
+func (w *walker) GetSkippedMounts() []string {
+	return w.skippedMounts
+}
+
+func (w *walker) GetIgnoreFiles() []string {
+	return w.ignoreFiles
 }
 
 func (w *walker) WalkDirs(fn WalkDirFunc, roots ...string) error {
@@ -55,6 +102,16 @@ func (w *walker) WalkDirs(fn WalkDirFunc, roots ...string) error {
 			return err
 		}
 		root = absRoot
+		if reason, skip := w.isSkippedMount(root); skip {
+			w.skippedMounts = append(w.skippedMounts, fmt.Sprintf("%s (%s)", root, reason))
+			continue
+		}
+		w.rootDevice = nil
+		if w.options.OneFileSystem {
+			if device, ok := DeviceId(root); ok {
+				w.rootDevice = &device
+			}
+		}
 		info, err := os.Lstat(root)
 		if err != nil {
 			err = w.walkDirEx(fn, root, root, nil, p, err)
@@ -65,7 +122,7 @@ func (w *walker) WalkDirs(fn WalkDirFunc, roots ...string) error {
 				root,
 				nil,
 				nil,
-			}, p, func(root string, path string, d DirEntryEx, p Progress, err error) error {
+			}, 0, p, func(root string, path string, d DirEntryEx, p Progress, err error) error {
 				return w.walkDirEx(fn, root, path, d, p, err)
 			})
 		}
@@ -79,6 +136,51 @@ func (w *walker) WalkDirs(fn WalkDirFunc, roots ...string) error {
 	return nil
 }
 
+func (w *walker) WalkPaths(fn WalkDirFunc, paths ...string) error {
+	p := &progress{
+		current: 0,
+		total:   len(paths),
+	}
+	for _, path := range paths {
+		p.Increment()
+		absPath, err := AbsolutePath(path)
+		if err != nil {
+			return err
+		}
+		if reason, skip := w.isSkippedMount(absPath); skip {
+			w.skippedMounts = append(w.skippedMounts, fmt.Sprintf("%s (%s)", absPath, reason))
+			continue
+		}
+		if w.options.IgnoreFiles {
+			w.loadIgnoreFiles(absPath)
+			if w.isParentIgnored(absPath) {
+				continue
+			}
+		}
+		info, err := os.Lstat(absPath)
+		if err != nil {
+			if !w.globMatcher.IsIncluded(absPath) || w.isIgnored(absPath, false) {
+				continue
+			}
+			if err := fn(absPath, absPath, 0, p); err != nil {
+				return err
+			}
+			continue
+		}
+		entry := fs.FileInfoToDirEntry(info)
+		err = w.walkDirEx(fn, "", absPath, &statDirEntryEx{
+			&statDirEntry{entry},
+			absPath,
+			nil,
+			nil,
+		}, p, nil)
+		if err != nil && err != filepath.SkipDir {
+			return err
+		}
+	}
+	return nil
+}
+
 func (w *walker) walkDirEx(fn WalkDirFunc, root string, path string, d DirEntryEx, p Progress, err error) error {
 	if _, seen := w.seenPaths[path]; seen {
 		if d.IsDir() {
@@ -87,7 +189,7 @@ func (w *walker) walkDirEx(fn WalkDirFunc, root string, path string, d DirEntryE
 		return nil
 	}
 	w.seenPaths[path] = struct{}{}
-	if !w.globMatcher.IsIncluded(path) {
+	if !w.globMatcher.IsIncluded(path) || w.isIgnored(path, d.IsDir()) {
 		if d.IsDir() {
 			return fs.SkipDir
 		}
@@ -96,8 +198,12 @@ func (w *walker) walkDirEx(fn WalkDirFunc, root string, path string, d DirEntryE
 	if d.IsDir() {
 		return nil
 	}
-	fileId, _ := filepath.Rel(root, path)
+	fileId := path
+	if len(root) > 0 {
+		fileId, _ = filepath.Rel(root, path)
+	}
 	filePath := path
+	fileType := d.DirEntry().Type()
 	if d.IsSymLink() {
 		targetPath, err := d.SymLinkTargetPath()
 		if targetPath == nil || err != nil {
@@ -106,12 +212,13 @@ func (w *walker) walkDirEx(fn WalkDirFunc, root string, path string, d DirEntryE
 		filePath = *targetPath
 		relTargetPath, _ := filepath.Rel(path, filePath)
 		fileId = fmt.Sprintf("%s (%s)", fileId, relTargetPath)
+		fileType = d.SymLinkTargetEntry().Type()
 	}
 	w.seenPaths[filePath] = struct{}{}
-	return fn(fileId, filePath, p)
+	return fn(fileId, filePath, fileType, p)
 }
 
-func (w *walker) walkDir(root string, path string, d DirEntryEx, p Progress, walkDirFn walkDirFunc) error {
+func (w *walker) walkDir(root string, path string, d DirEntryEx, depth int, p Progress, walkDirFn walkDirFunc) error {
 	p.Increment()
 	if err := walkDirFn(root, path, d, p, nil); err != nil || !d.IsDir() {
 		if err == filepath.SkipDir && d.IsDir() {
@@ -132,6 +239,17 @@ func (w *walker) walkDir(root string, path string, d DirEntryEx, p Progress, wal
 		}
 		targetPath = symLinkTargetPath
 	}
+	if w.options.MaxDepth >= 0 && depth >= w.options.MaxDepth {
+		return nil
+	}
+	realPath := path
+	if targetPath != nil {
+		realPath = *targetPath
+	}
+	if reason, skip := w.isFilesystemBoundary(realPath); skip {
+		w.skippedMounts = append(w.skippedMounts, fmt.Sprintf("%s (%s)", path, reason))
+		return nil
+	}
 	dirs, err := readDir(dirToRead, targetPath)
 	p.AddToTotal(len(dirs))
 	if err != nil {
@@ -140,9 +258,12 @@ func (w *walker) walkDir(root string, path string, d DirEntryEx, p Progress, wal
 			return err
 		}
 	}
+	if w.options.IgnoreFiles && w.pushIgnoreFile(path, realPath, dirs) {
+		defer w.popIgnoreFile()
+	}
 	for _, d1 := range dirs {
 		path1 := filepath.Join(path, d1.DirEntry().Name())
-		if err := w.walkDir(root, path1, d1, p, walkDirFn); err != nil {
+		if err := w.walkDir(root, path1, d1, depth+1, p, walkDirFn); err != nil {
 			if err == filepath.SkipDir {
 				break
 			}
@@ -152,6 +273,127 @@ func (w *walker) walkDir(root string, path string, d DirEntryEx, p Progress, wal
 	return nil
 }
 
+func (w *walker) pushIgnoreFile(path string, realPath string, dirs []DirEntryEx) bool {
+	for _, d := range dirs {
+		if d.DirEntry().Name() != IgnoreFileName || d.IsDir() {
+			continue
+		}
+		ignoreMatcher := w.loadIgnoreFile(path, realPath)
+		if ignoreMatcher == nil {
+			return false
+		}
+		w.ignores = append(w.ignores, ignoreMatcher)
+		return true
+	}
+	return false
+}
+
+// loadIgnoreFiles loads the ignore files of the directories above the path, as WalkPaths visits
+// paths without walking the directories containing them.
+func (w *walker) loadIgnoreFiles(path string) {
+	var dirs []string
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	w.ignores = nil
+	for _, dir := range dirs {
+		ignoreMatcher, loaded := w.ignoreDirs[dir]
+		if !loaded {
+			if info, err := os.Stat(filepath.Join(dir, IgnoreFileName)); err == nil && !info.IsDir() {
+				ignoreMatcher = w.loadIgnoreFile(dir, dir)
+			}
+			w.ignoreDirs[dir] = ignoreMatcher
+		}
+		if ignoreMatcher != nil {
+			w.ignores = append(w.ignores, ignoreMatcher)
+		}
+	}
+}
+
+// loadIgnoreFile loads the ignore file of the directory and records it, returning nil if it could
+// not be loaded.
+func (w *walker) loadIgnoreFile(path string, realPath string) IgnoreMatcher {
+	ignoreFile := filepath.Join(path, IgnoreFileName)
+	ignoreMatcher, err := NewIgnoreMatcherFromFile(filepath.Join(realPath, IgnoreFileName), path)
+	if err != nil {
+		w.ignoreFiles = append(w.ignoreFiles, fmt.Sprintf("%s (failed to load: %v)", ignoreFile, err))
+		return nil
+	}
+	w.ignoreFiles = append(w.ignoreFiles, ignoreFile)
+	return ignoreMatcher
+}
+
+func (w *walker) popIgnoreFile() {
+	w.ignores = w.ignores[:len(w.ignores)-1]
+}
+
+func (w *walker) isIgnored(path string, isDir bool) bool {
+	ignored := false
+	for _, ignore := range w.ignores {
+		if matched, i := ignore.Match(path, isDir); matched {
+			ignored = i
+		}
+	}
+	return ignored
+}
+
+// isParentIgnored reports whether any directory above the path is ignored, as the contents of an
+// ignored directory cannot be re-included.
+func (w *walker) isParentIgnored(path string) bool {
+	for dir := filepath.Dir(path); filepath.Dir(dir) != dir; dir = filepath.Dir(dir) {
+		if w.isIgnored(dir, true) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *walker) isFilesystemBoundary(path string) (string, bool) {
+	if w.rootDevice != nil {
+		if device, ok := DeviceId(path); ok && device != *w.rootDevice {
+			return "different filesystem", true
+		}
+	}
+	if fsType, ok := w.mounts[path]; ok {
+		if _, skip := w.skipFsTypes[fsType]; skip {
+			return fmt.Sprintf("%s filesystem", fsType), true
+		}
+	}
+	return "", false
+}
+
+// isSkippedMount reports whether the path, once its symlinks are resolved, lies within a mount whose
+// filesystem type is skipped.  Unlike isFilesystemBoundary, it is not limited to mount points, so
+// roots below a skipped mount, such as /proc/self, are also skipped.
+func (w *walker) isSkippedMount(path string) (string, bool) {
+	if len(w.skipFsTypes) == 0 {
+		return "", false
+	}
+	if realPath, err := filepath.EvalSymlinks(path); err == nil {
+		path = realPath
+	}
+	mountPoint := ""
+	fsType := ""
+	for m, t := range w.mounts {
+		if len(m) >= len(mountPoint) && isWithinMount(path, m) {
+			mountPoint = m
+			fsType = t
+		}
+	}
+	if _, skip := w.skipFsTypes[fsType]; skip && len(mountPoint) > 0 {
+		return fmt.Sprintf("%s filesystem mounted at %s", fsType, mountPoint), true
+	}
+	return "", false
+}
+
+// isWithinMount reports whether the path is the mount point, or lies below it.
+func isWithinMount(path string, mountPoint string) bool {
+	return path == mountPoint || strings.HasPrefix(path, strings.TrimSuffix(mountPoint, "/")+"/")
+}
+
 func readDir(dirname string, targetPath *string) ([]DirEntryEx, error) {
 	dirToRead := dirname
 	if targetPath != nil {
@@ -85,6 +85,23 @@ func (r *zipReader) Files() FileIterable {
 	}
 }
 
+func (r *zipReader) Names() []string {
+	names := make([]string, 0, len(r.reader.File))
+	for _, file := range r.reader.File {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+func (r *zipReader) Open(name string) (ContentFile, error) {
+	for _, file := range r.reader.File {
+		if file.Name == name {
+			return NewZipFile(file)
+		}
+	}
+	return nil, fmt.Errorf("zip content %s does not exist", name)
+}
+
 func (r *zipReader) Filename() string {
 	return r.filename
 }
@@ -93,6 +110,10 @@ func (r *zipReader) Hash() (string, error) {
 	return r.contentFileReader.Hash()
 }
 
+func (r *zipReader) Hashes() (Digests, error) {
+	return r.contentFileReader.Hashes()
+}
+
 func (r *zipReader) Close() error {
 	err := r.contentFileReader.Close()
 	if err != nil {
@@ -119,13 +140,13 @@ type zipReaderFileIterable struct {
 
 func (i *zipReaderFileIterable) Next() (interface{}, error) {
 	for {
-		if i.index >= len(i.files)-1 {
+		if i.index >= len(i.files) {
 			return nil, nil
 		}
 		current := i.index
 		i.index += 1
 		currentFile := i.files[current]
-		if currentFile.FileInfo().IsDir() || i.globMatcher.IsIncluded(currentFile.Name) {
+		if currentFile.FileInfo().IsDir() || !i.globMatcher.IsIncluded(currentFile.Name) {
 			continue
 		}
 		return NewZipFile(currentFile)
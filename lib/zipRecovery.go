@@ -0,0 +1,371 @@
+// Copyright © 2021 Joel Baranick <jbaranick@gmail.com>
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+// 	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	zipLocalFileHeaderSize       = 30
+	zipEndOfCentralDirectorySize = 22
+	zipMaxCommentSize            = 0xFFFF
+	zipDataDescriptorFlag        = 0x8
+	zipEncryptedFlag             = 0x1
+	zip64ExtraId                 = 0x0001
+	zipResyncChunkSize           = 32 * 1024
+)
+
+var (
+	zipLocalFileHeaderSignature       = []byte("PK\x03\x04")
+	zipEndOfCentralDirectorySignature = []byte("PK\x05\x06")
+	// selfExtractingExtensions are the extensions of installers which may have a zip appended, and are
+	// buffered to look for one when found within an archive.
+	selfExtractingExtensions = map[string]struct{}{
+		".bin": {},
+		".sh":  {},
+		".run": {},
+		".exe": {},
+	}
+)
+
+// findEmbeddedZip returns the offset at which a zip appended to other content, such as the shell
+// script of a self-extracting installer, starts.  The end of central directory record is searched
+// for within the trailing comment sized window of the content, and the start derived from the size
+// and offset of the central directory it declares.
+func findEmbeddedZip(reader io.ReaderAt, size int64) (int64, bool) {
+	length := int64(zipEndOfCentralDirectorySize + zipMaxCommentSize)
+	if length > size {
+		length = size
+	}
+	if length < zipEndOfCentralDirectorySize {
+		return 0, false
+	}
+	tail := make([]byte, length)
+	if _, err := reader.ReadAt(tail, size-length); err != nil && err != io.EOF {
+		return 0, false
+	}
+	for i := len(tail) - zipEndOfCentralDirectorySize; i >= 0; i-- {
+		if !bytes.Equal(tail[i:i+4], zipEndOfCentralDirectorySignature) {
+			continue
+		}
+		commentLength := int(binary.LittleEndian.Uint16(tail[i+20:]))
+		if i+zipEndOfCentralDirectorySize+commentLength > len(tail) {
+			continue
+		}
+		directorySize := int64(binary.LittleEndian.Uint32(tail[i+12:]))
+		directoryOffset := int64(binary.LittleEndian.Uint32(tail[i+16:]))
+		base := size - length + int64(i) - directorySize - directoryOffset
+		if base < 0 {
+			// The offsets were adjusted to include the prepended content, as by zip -A.
+			base = 0
+		}
+		return base, true
+	}
+	return 0, false
+}
+
+// isSelfExtractingName reports whether the file name is that of an installer which may have a zip
+// appended.
+func isSelfExtractingName(filename string) bool {
+	_, ok := selfExtractingExtensions[strings.ToLower(filepath.Ext(filename))]
+	return ok
+}
+
+type recoveredZipEntry struct {
+	name             string
+	method           uint16
+	offset           int64
+	compressedSize   int64
+	uncompressedSize int64
+}
+
+// recoverZipEntries walks the local file headers of a zip whose central directory is missing or
+// corrupted.  Unreadable data is skipped up to the next local file header, entries whose size is
+// only declared by a trailing data descriptor are measured by inflating them, and encrypted entries,
+// or those using compression methods other than store and deflate, are omitted.
+func recoverZipEntries(reader io.ReaderAt, size int64) []recoveredZipEntry {
+	var entries []recoveredZipEntry
+	header := make([]byte, zipLocalFileHeaderSize)
+	offset, ok := findZipSignature(reader, 0, size)
+	for ok && offset+zipLocalFileHeaderSize <= size {
+		if _, err := reader.ReadAt(header, offset); err != nil {
+			break
+		}
+		flags := binary.LittleEndian.Uint16(header[6:])
+		method := binary.LittleEndian.Uint16(header[8:])
+		compressedSize := int64(binary.LittleEndian.Uint32(header[18:]))
+		uncompressedSize := int64(binary.LittleEndian.Uint32(header[22:]))
+		nameLength := int64(binary.LittleEndian.Uint16(header[26:]))
+		extraLength := int64(binary.LittleEndian.Uint16(header[28:]))
+		variable := make([]byte, nameLength+extraLength)
+		if _, err := reader.ReadAt(variable, offset+zipLocalFileHeaderSize); err != nil {
+			break
+		}
+		if compressedSize == 0xFFFFFFFF || uncompressedSize == 0xFFFFFFFF {
+			uncompressedSize, compressedSize = zip64Sizes(variable[nameLength:], uncompressedSize, compressedSize)
+		}
+		dataOffset := offset + zipLocalFileHeaderSize + nameLength + extraLength
+		if flags&zipDataDescriptorFlag != 0 && compressedSize == 0 {
+			if method != zip.Deflate {
+				offset, ok = findZipSignature(reader, dataOffset, size)
+				continue
+			}
+			var measured bool
+			if compressedSize, uncompressedSize, measured = measureDeflate(reader, dataOffset, size); !measured {
+				offset, ok = findZipSignature(reader, dataOffset, size)
+				continue
+			}
+		}
+		if dataOffset+compressedSize > size {
+			break
+		}
+		if flags&zipEncryptedFlag == 0 && (method == zip.Store || method == zip.Deflate) {
+			entries = append(entries, recoveredZipEntry{
+				name:             string(variable[:nameLength]),
+				method:           method,
+				offset:           dataOffset,
+				compressedSize:   compressedSize,
+				uncompressedSize: uncompressedSize,
+			})
+		}
+		offset, ok = findZipSignature(reader, dataOffset+compressedSize, size)
+	}
+	return entries
+}
+
+// findZipSignature returns the offset of the next local file header at, or after, offset.
+func findZipSignature(reader io.ReaderAt, offset int64, size int64) (int64, bool) {
+	chunk := make([]byte, zipResyncChunkSize)
+	for offset < size {
+		n, err := reader.ReadAt(chunk, offset)
+		if n == 0 {
+			return 0, false
+		}
+		if i := bytes.Index(chunk[:n], zipLocalFileHeaderSignature); i != -1 {
+			return offset + int64(i), true
+		}
+		if err != nil {
+			return 0, false
+		}
+		// Overlap the chunks so that a signature spanning them is found.
+		offset += int64(n - len(zipLocalFileHeaderSignature) + 1)
+	}
+	return 0, false
+}
+
+// zip64Sizes returns the sizes declared by the zip64 extra field, which holds the uncompressed,
+// then compressed, size when the header declares them as 0xFFFFFFFF.
+func zip64Sizes(extra []byte, uncompressedSize int64, compressedSize int64) (int64, int64) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		length := int(binary.LittleEndian.Uint16(extra[2:]))
+		if 4+length > len(extra) {
+			break
+		}
+		field := extra[4 : 4+length]
+		extra = extra[4+length:]
+		if id != zip64ExtraId {
+			continue
+		}
+		if uncompressedSize == 0xFFFFFFFF && len(field) >= 8 {
+			uncompressedSize = int64(binary.LittleEndian.Uint64(field))
+			field = field[8:]
+		}
+		if compressedSize == 0xFFFFFFFF && len(field) >= 8 {
+			compressedSize = int64(binary.LittleEndian.Uint64(field))
+		}
+	}
+	return uncompressedSize, compressedSize
+}
+
+// countingByteReader counts the bytes read through it.  Being an io.ByteReader, flate reads no
+// further than the end of the deflate stream.
+type countingByteReader struct {
+	reader *bufio.Reader
+	count  int64
+}
+
+func (r *countingByteReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+func (r *countingByteReader) ReadByte() (byte, error) {
+	b, err := r.reader.ReadByte()
+	if err == nil {
+		r.count += 1
+	}
+	return b, err
+}
+
+// measureDeflate returns the compressed and uncompressed sizes of the deflate stream at offset.
+func measureDeflate(reader io.ReaderAt, offset int64, size int64) (int64, int64, bool) {
+	counter := &countingByteReader{reader: bufio.NewReader(io.NewSectionReader(reader, offset, size-offset))}
+	inflater := flate.NewReader(counter)
+	defer func(inflater io.ReadCloser) {
+		_ = inflater.Close()
+	}(inflater)
+	uncompressedSize, err := io.Copy(io.Discard, inflater)
+	if err != nil {
+		return 0, 0, false
+	}
+	return counter.count, uncompressedSize, true
+}
+
+type recoveredZipFile struct {
+	entry  recoveredZipEntry
+	reader ContentFileReader
+}
+
+func (f *recoveredZipFile) Name() string {
+	return f.entry.name
+}
+
+func (f *recoveredZipFile) IsDir() bool {
+	return strings.HasSuffix(f.entry.name, "/")
+}
+
+func (f *recoveredZipFile) UncompressedSize() int64 {
+	return f.entry.uncompressedSize
+}
+
+func (f *recoveredZipFile) Reader() ContentFileReader {
+	return f.reader
+}
+
+func (f *recoveredZipFile) Close() error {
+	return f.reader.Close()
+}
+
+// recoveredZipReader reads the entries of a zip recovered from its local file headers.
+type recoveredZipReader struct {
+	reader            io.ReaderAt
+	entries           []recoveredZipEntry
+	contentFileReader ContentFileReader
+	closers           []io.Closer
+	globMatcher       GlobMatcher
+	filename          string
+}
+
+// NewRecoveredZipReader creates a reader of the zip content, of the given size, whose central
+// directory could not be read.  It returns an error when no entries could be recovered.
+func NewRecoveredZipReader(filename string, reader io.ReaderAt, size int64, contentFileReader ContentFileReader, globMatcher GlobMatcher, closers ...io.Closer) (ContentReader, error) {
+	entries := recoverZipEntries(reader, size)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no entries could be recovered")
+	}
+	return &recoveredZipReader{
+		reader:            reader,
+		entries:           entries,
+		contentFileReader: contentFileReader,
+		closers:           closers,
+		globMatcher:       globMatcher,
+		filename:          filename,
+	}, nil
+}
+
+// IsRecovered reports whether the entries of the archive were recovered from a corrupted zip.
+func IsRecovered(reader ContentReader) bool {
+	_, ok := reader.(*recoveredZipReader)
+	return ok
+}
+
+func (r *recoveredZipReader) open(entry recoveredZipEntry) (ContentFile, error) {
+	var content io.ReadCloser = io.NopCloser(io.NewSectionReader(r.reader, entry.offset, entry.compressedSize))
+	if entry.method == zip.Deflate {
+		content = flate.NewReader(content)
+	}
+	contentFileReader, err := NewContentFileReader(entry.name, entry.uncompressedSize, NewUnbufferedReadCloser(content))
+	if err != nil {
+		_ = content.Close()
+		return nil, fmt.Errorf("unable to open recovered zip content %s:\n%v", entry.name, err)
+	}
+	return &recoveredZipFile{entry: entry, reader: contentFileReader}, nil
+}
+
+func (r *recoveredZipReader) Files() FileIterable {
+	return &recoveredZipReaderFileIterable{reader: r}
+}
+
+func (r *recoveredZipReader) Names() []string {
+	names := make([]string, 0, len(r.entries))
+	for _, entry := range r.entries {
+		names = append(names, entry.name)
+	}
+	return names
+}
+
+func (r *recoveredZipReader) Open(name string) (ContentFile, error) {
+	for _, entry := range r.entries {
+		if entry.name == name {
+			return r.open(entry)
+		}
+	}
+	return nil, fmt.Errorf("recovered zip content %s does not exist", name)
+}
+
+func (r *recoveredZipReader) Filename() string {
+	return r.filename
+}
+
+func (r *recoveredZipReader) Hash() (string, error) {
+	return r.contentFileReader.Hash()
+}
+
+func (r *recoveredZipReader) Hashes() (Digests, error) {
+	return r.contentFileReader.Hashes()
+}
+
+func (r *recoveredZipReader) Close() error {
+	err := r.contentFileReader.Close()
+	for _, closer := range r.closers {
+		if nextErr := closer.Close(); nextErr != nil {
+			if err == nil {
+				err = nextErr
+			} else {
+				err = fmt.Errorf("%v: %v", err, nextErr)
+			}
+		}
+	}
+	return err
+}
+
+type recoveredZipReaderFileIterable struct {
+	index  int
+	reader *recoveredZipReader
+}
+
+func (i *recoveredZipReaderFileIterable) Next() (interface{}, error) {
+	for i.index < len(i.reader.entries) {
+		entry := i.reader.entries[i.index]
+		i.index += 1
+		if strings.HasSuffix(entry.name, "/") || !i.reader.globMatcher.IsIncluded(entry.name) {
+			continue
+		}
+		return i.reader.open(entry)
+	}
+	return nil, nil
+}